@@ -0,0 +1,182 @@
+package openload
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CaptchaSolver resolves download ticket captchas.
+// image holds the captcha image bytes as served by CaptchaURL,
+// width and height are CaptchaW and CaptchaH of the ticket.
+type CaptchaSolver interface {
+	SolveCaptcha(ctx context.Context, image []byte, width, height int) (string, error)
+}
+
+// CaptchaSolverFunc adapts a function to CaptchaSolver.
+type CaptchaSolverFunc func(ctx context.Context, image []byte, width, height int) (string, error)
+
+// SolveCaptcha calls f.
+func (f CaptchaSolverFunc) SolveCaptcha(ctx context.Context, image []byte, width, height int) (string, error) {
+	return f(ctx, image, width, height)
+}
+
+// FailFastSolver never solves captchas, it fails with ErrCaptchaRequired
+// like a download without solver, it suits unattended jobs.
+type FailFastSolver struct{}
+
+// SolveCaptcha returns ErrCaptchaRequired.
+func (FailFastSolver) SolveCaptcha(ctx context.Context, image []byte, width, height int) (string, error) {
+	return "", ErrCaptchaRequired
+}
+
+// PromptSolver saves the captcha image to a temporary file
+// and prompts for the answer on a terminal.
+// It reads In through one buffered reader and must be used as a pointer:
+//
+//	opts := openload.DownloadOptions{CaptchaSolver: &openload.PromptSolver{}}
+type PromptSolver struct {
+	// In is where the answer line is read from, defaults to os.Stdin.
+	In io.Reader
+	// Out is where the prompt is written, defaults to os.Stderr.
+	Out io.Writer
+	// Dir is the directory of the image file, defaults to os.TempDir().
+	Dir string
+
+	mu sync.Mutex
+	r  *bufio.Reader
+	// pending is the line of a read left by a cancelled prompt,
+	// it answers the next prompt.
+	pending chan promptLine
+}
+
+type promptLine struct {
+	line string
+	err  error
+}
+
+// SolveCaptcha saves image, prompts for the answer and reads one line.
+func (s *PromptSolver) SolveCaptcha(ctx context.Context, image []byte, width, height int) (string, error) {
+	in, out := s.In, s.Out
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stderr
+	}
+	f, err := ioutil.TempFile(s.Dir, "openload-captcha-*.png")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(image)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(out, "captcha image (%dx%d) saved to %s\nanswer: ", width, height, f.Name())
+	return s.readLine(ctx, in)
+}
+
+// readLine reads one answer line from in, a read can not be interrupted
+// so when ctx is done it is kept pending for the next prompt.
+func (s *PromptSolver) readLine(ctx context.Context, in io.Reader) (string, error) {
+	s.mu.Lock()
+	if s.r == nil {
+		s.r = bufio.NewReader(in)
+	}
+	if s.pending == nil {
+		pending := make(chan promptLine, 1)
+		s.pending = pending
+		r := s.r
+		go func() {
+			line, err := r.ReadString('\n')
+			pending <- promptLine{line: line, err: err}
+		}()
+	}
+	pending := s.pending
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case l := <-pending:
+		s.mu.Lock()
+		s.pending = nil
+		s.mu.Unlock()
+		if l.err != nil && (l.err != io.EOF || l.line == "") {
+			return "", l.err
+		}
+		return strings.TrimSpace(l.line), nil
+	}
+}
+
+// FileSolver writes the captcha image to ImagePath and waits for
+// the answer to be written to AnswerPath by another process or a person,
+// the answer file is removed once read.
+type FileSolver struct {
+	ImagePath  string
+	AnswerPath string
+	// PollInterval is how often AnswerPath is checked, defaults to one second.
+	PollInterval time.Duration
+}
+
+// SolveCaptcha removes AnswerPath, writes image and polls AnswerPath
+// until it is not empty or ctx is done.
+func (s FileSolver) SolveCaptcha(ctx context.Context, image []byte, width, height int) (string, error) {
+	// An answer left by a previous captcha must not answer this one.
+	if err := os.Remove(s.AnswerPath); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := ioutil.WriteFile(s.ImagePath, image, 0644); err != nil {
+		return "", err
+	}
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		data, err := ioutil.ReadFile(s.AnswerPath)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if answer := strings.TrimSpace(string(data)); answer != "" {
+			os.Remove(s.AnswerPath)
+			return answer, nil
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return "", err
+		}
+	}
+}
+
+// solveCaptcha fetches the ticket captcha image and passes it to solver.
+func (c *Client) solveCaptcha(ctx context.Context, solver CaptchaSolver, ticket *DownloadTicketResponse) (string, error) {
+	request, err := c.newRequest(ctx, http.MethodGet, string(ticket.CaptchaURL), nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openload: captcha image request failed: %s", response.Status)
+	}
+	image, err := ioutil.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	return solver.SolveCaptcha(ctx, image, int(ticket.CaptchaW), int(ticket.CaptchaH))
+}
@@ -0,0 +1,135 @@
+package openload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadCaptchaSolver(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.captchaURL = fmt.Sprintf("%q", s.URL+"/captcha.png")
+	s.captcha = "captchas suck"
+
+	var buf bytes.Buffer
+	var solved int
+	solver := CaptchaSolverFunc(func(ctx context.Context, image []byte, width, height int) (string, error) {
+		solved++
+		assert.EqualValues(t, "PNG", string(image))
+		assert.EqualValues(t, 140, width)
+		assert.EqualValues(t, 70, height)
+		if solved == 1 {
+			return "wrong", nil
+		}
+		return "captchas suck", nil
+	})
+	_, err := s.client().Download(context.Background(), "72fA-_Lq8Ak", &buf, DownloadOptions{CaptchaSolver: solver})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, solved)
+	assert.EqualValues(t, 2, s.tickets)
+	assert.EqualValues(t, "The quick brown fox", buf.String())
+}
+
+func TestFailFastSolver(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.captchaURL = fmt.Sprintf("%q", s.URL+"/captcha.png")
+
+	_, err := s.client().Download(context.Background(), "72fA-_Lq8Ak", ioutil.Discard, DownloadOptions{CaptchaSolver: FailFastSolver{}})
+
+	assert.True(t, errors.Is(err, ErrCaptchaRequired))
+}
+
+func TestPromptSolver(t *testing.T) {
+	var out bytes.Buffer
+	solver := &PromptSolver{In: strings.NewReader(" captchas suck \nsecond\n"), Out: &out, Dir: t.TempDir()}
+
+	answer, err := solver.SolveCaptcha(context.Background(), []byte("PNG"), 140, 70)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "captchas suck", answer)
+	assert.Contains(t, out.String(), "140x70")
+
+	answer, err = solver.SolveCaptcha(context.Background(), []byte("PNG"), 140, 70)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "second", answer)
+}
+
+func TestPromptSolverCanceled(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	solver := &PromptSolver{In: r, Out: ioutil.Discard, Dir: t.TempDir()}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := solver.SolveCaptcha(ctx, []byte("PNG"), 140, 70)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// The read left by the cancelled prompt answers the next one.
+	go w.Write([]byte("captchas suck\n"))
+	answer, err := solver.SolveCaptcha(context.Background(), []byte("PNG"), 140, 70)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "captchas suck", answer)
+}
+
+func TestFileSolver(t *testing.T) {
+	dir := t.TempDir()
+	solver := FileSolver{
+		ImagePath:    filepath.Join(dir, "captcha.png"),
+		AnswerPath:   filepath.Join(dir, "answer.txt"),
+		PollInterval: time.Millisecond,
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ioutil.WriteFile(solver.AnswerPath, []byte("captchas suck\n"), 0644)
+	}()
+
+	answer, err := solver.SolveCaptcha(context.Background(), []byte("PNG"), 140, 70)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "captchas suck", answer)
+	image, err := ioutil.ReadFile(solver.ImagePath)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "PNG", string(image))
+}
+
+func TestFileSolverStaleAnswer(t *testing.T) {
+	dir := t.TempDir()
+	solver := FileSolver{
+		ImagePath:    filepath.Join(dir, "captcha.png"),
+		AnswerPath:   filepath.Join(dir, "answer.txt"),
+		PollInterval: time.Millisecond,
+	}
+	assert.Nil(t, ioutil.WriteFile(solver.AnswerPath, []byte("stale\n"), 0644))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ioutil.WriteFile(solver.AnswerPath, []byte("captchas suck\n"), 0644)
+	}()
+
+	answer, err := solver.SolveCaptcha(context.Background(), []byte("PNG"), 140, 70)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "captchas suck", answer)
+}
+
+func TestFileSolverCanceled(t *testing.T) {
+	dir := t.TempDir()
+	solver := FileSolver{ImagePath: filepath.Join(dir, "captcha.png"), AnswerPath: filepath.Join(dir, "answer.txt")}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := solver.SolveCaptcha(ctx, []byte("PNG"), 140, 70)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
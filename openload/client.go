@@ -1,17 +1,18 @@
 package openload
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -20,30 +21,52 @@ const (
 )
 
 func buildAPIURL() string {
-	return fmt.Sprintf("%s/%s", apiBaseURL, apiVersion)
+	return joinAPIURL(apiBaseURL, apiVersion)
+}
+
+func joinAPIURL(baseURL, version string) string {
+	if version == "" {
+		return baseURL
+	}
+	return fmt.Sprintf("%s/%s", baseURL, version)
 }
 
 func checkStatus(status int, msg string) error {
 	if status != http.StatusOK {
-		return errors.New(msg)
+		return &APIError{Status: status, Msg: msg}
 	}
 	return nil
 }
 
 // Client represents openload api client.
 type Client struct {
-	login      string
-	key        string
-	api        string
-	httpClient *http.Client
+	login           string
+	key             string
+	api             string
+	baseURL         string
+	apiVersion      string
+	userAgent       string
+	httpClient      *http.Client
+	retry           *RetryPolicy
+	limiters        map[EndpointGroup]*limiter
+	location        *time.Location
+	walkConcurrency int
+	listings        *listingCache
+	infoBatchSize   int
+	infoConcurrency int
 }
 
 // AccountInfo requests logged-in account info
 // And returns AccountInfo object holding infos.
 // https://openload.co/api#accountinfos
 func (c *Client) AccountInfo() (*AccountInfoResponse, error) {
+	return c.AccountInfoContext(context.Background())
+}
+
+// AccountInfoContext is like AccountInfo but uses ctx for the request.
+func (c *Client) AccountInfoContext(ctx context.Context) (*AccountInfoResponse, error) {
 	var info AccountInfoResponse
-	if err := c.get("/account/info", nil, &info); err != nil {
+	if err := c.getIdempotent(ctx, "/account/info", nil, &info); err != nil {
 		return nil, err
 	}
 	return &info, nil
@@ -53,8 +76,13 @@ func (c *Client) AccountInfo() (*AccountInfoResponse, error) {
 // This ticket will be used for actual download.
 // https://openload.co/api#download-ticket
 func (c *Client) DownloadTicket(fileID string) (*DownloadTicketResponse, error) {
+	return c.DownloadTicketContext(context.Background(), fileID)
+}
+
+// DownloadTicketContext is like DownloadTicket but uses ctx for the request.
+func (c *Client) DownloadTicketContext(ctx context.Context, fileID string) (*DownloadTicketResponse, error) {
 	var ticket DownloadTicketResponse
-	if err := c.get("/file/dlticket", map[string]string{"file": fileID}, &ticket); err != nil {
+	if err := c.get(ctx, "/file/dlticket", map[string]string{"file": fileID}, &ticket); err != nil {
 		return nil, err
 	}
 	return &ticket, nil
@@ -68,8 +96,13 @@ func (c *Client) DownloadTicket(fileID string) (*DownloadTicketResponse, error)
 // field in openload API documentation sometimes download ticket has no captcha.
 // https://openload.co/api#download-getlink
 func (c *Client) DownloadLink(fileID string, ticket string, captchaResponse string) (*DownloadLinkResponse, error) {
+	return c.DownloadLinkContext(context.Background(), fileID, ticket, captchaResponse)
+}
+
+// DownloadLinkContext is like DownloadLink but uses ctx for the request.
+func (c *Client) DownloadLinkContext(ctx context.Context, fileID string, ticket string, captchaResponse string) (*DownloadLinkResponse, error) {
 	var link DownloadLinkResponse
-	if err := c.get("/file/dl", map[string]string{"file": fileID, "ticket": ticket, "captcha_response": captchaResponse}, &link); err != nil {
+	if err := c.get(ctx, "/file/dl", map[string]string{"file": fileID, "ticket": ticket, "captcha_response": captchaResponse}, &link); err != nil {
 		return nil, err
 	}
 	return &link, nil
@@ -78,7 +111,16 @@ func (c *Client) DownloadLink(fileID string, ticket string, captchaResponse stri
 // FileInfo requests a single file info.
 // https://openload.co/api#download-info
 func (c *Client) FileInfo(fileID string) (*FileInfoResponse, error) {
-	infos, err := c.FilesInfo([]string{fileID})
+	return c.FileInfoContext(context.Background(), fileID)
+}
+
+// FileInfoContext is like FileInfo but uses ctx for the request.
+func (c *Client) FileInfoContext(ctx context.Context, fileID string) (*FileInfoResponse, error) {
+	infos, err := c.FilesInfoContext(ctx, []string{fileID})
+	var infoErr *FilesInfoError
+	if errors.As(err, &infoErr) {
+		return nil, infoErr.Errors[fileID]
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -92,21 +134,31 @@ func (c *Client) FileInfo(fileID string) (*FileInfoResponse, error) {
 }
 
 // FilesInfo requests info for a list of files.
+// Large lists are split into batches requested concurrently
+// (see WithFilesInfoBatching) and merged into one response.
+// Ids missing from the response or whose batch failed are reported
+// by a *FilesInfoError returned along with the info of the other ids.
 // https://openload.co/api#download-info
 func (c *Client) FilesInfo(filesID []string) (FilesInfoResponse, error) {
-	var info FilesInfoResponse
-	if err := c.get("/file/info", map[string]string{"file": strings.Join(filesID, ",")}, &info); err != nil {
-		return nil, err
-	}
-	return info, nil
+	return c.FilesInfoContext(context.Background(), filesID)
+}
+
+// FilesInfoContext is like FilesInfo but uses ctx for the request.
+func (c *Client) FilesInfoContext(ctx context.Context, filesID []string) (FilesInfoResponse, error) {
+	return c.filesInfo(ctx, filesID)
 }
 
 // UploadLink requests an upload URL
 // This URL will be used to perform actual upload.
 // https://openload.co/api#upload
 func (c *Client) UploadLink(folderID string, sha1 string, httponly bool) (*UploadURLResponse, error) {
+	return c.UploadLinkContext(context.Background(), folderID, sha1, httponly)
+}
+
+// UploadLinkContext is like UploadLink but uses ctx for the request.
+func (c *Client) UploadLinkContext(ctx context.Context, folderID string, sha1 string, httponly bool) (*UploadURLResponse, error) {
 	var link UploadURLResponse
-	if err := c.get("/file/ul", map[string]string{"folder": folderID, "sha1": sha1, "httponly": strconv.FormatBool(httponly)}, &link); err != nil {
+	if err := c.getIdempotent(ctx, "/file/ul", map[string]string{"folder": folderID, "sha1": sha1, "httponly": strconv.FormatBool(httponly)}, &link); err != nil {
 		return nil, err
 	}
 	return &link, nil
@@ -118,47 +170,28 @@ func (c *Client) UploadLink(folderID string, sha1 string, httponly bool) (*Uploa
 // httponly is optional pass false if not needed.
 // https://openload.co/api#upload
 func (c *Client) Upload(name string, folderID string, sha1 string, httponly bool) (*UploadResponse, error) {
-	var result UploadResponse
+	return c.UploadContext(context.Background(), name, folderID, sha1, httponly)
+}
 
-	// Get valid upload link.
-	ul, err := c.UploadLink(folderID, sha1, httponly)
+// UploadContext is like Upload but uses ctx for both the upload link request
+// and the upload itself, cancelling ctx aborts a running upload.
+func (c *Client) UploadContext(ctx context.Context, name string, folderID string, sha1 string, httponly bool) (*UploadResponse, error) {
+	// Fail before requesting an upload link if the file is not readable.
+	file, err := os.Open(name)
 	if err != nil {
 		return nil, err
 	}
-
-	// Read the file.
-	r, w := io.Pipe()
-	m := multipart.NewWriter(w)
-
-	go func() {
-		defer w.Close()
-		defer m.Close()
-
-		part, err := m.CreateFormFile("files", path.Base(name))
-		if err != nil {
-			return
-		}
-		file, err := os.Open(name)
-		if err != nil {
-			return
-		}
-		defer file.Close()
-		if _, err = io.Copy(part, file); err != nil {
-			return
-		}
-	}()
-
-	// Upload the file and process the response.
-	response, err := c.httpClient.Post(ul.URL, m.FormDataContentType(), r)
+	defer file.Close()
+	info, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	err = processResponse(response.Body, &result)
-	if err != nil {
-		return nil, err
+	if info.IsDir() {
+		return nil, fmt.Errorf("openload: %s is a directory", name)
 	}
-	return &result, nil
+
+	opts := UploadOptions{FolderID: folderID, Sha1: sha1, HTTPOnly: httponly, Size: info.Size()}
+	return c.upload(ctx, path.Base(name), file, opts)
 }
 
 // RemoteUpload adds a remote upload
@@ -166,6 +199,11 @@ func (c *Client) Upload(name string, folderID string, sha1 string, httponly bool
 // headers is optional pass nil or empty map if not needed.
 // https://openload.co/api#remoteul-add
 func (c *Client) RemoteUpload(url string, folderID string, headers map[string]string) (*RemoteUploadResponse, error) {
+	return c.RemoteUploadContext(context.Background(), url, folderID, headers)
+}
+
+// RemoteUploadContext is like RemoteUpload but uses ctx for the request.
+func (c *Client) RemoteUploadContext(ctx context.Context, url string, folderID string, headers map[string]string) (*RemoteUploadResponse, error) {
 	var remote RemoteUploadResponse
 	params := map[string]string{"url": url}
 	if folderID != "" {
@@ -178,7 +216,7 @@ func (c *Client) RemoteUpload(url string, folderID string, headers map[string]st
 		}
 		params["headers"] = strings.Join(h, "\n")
 	}
-	if err := c.get("/remotedl/add", params, &remote); err != nil {
+	if err := c.get(ctx, "/remotedl/add", params, &remote); err != nil {
 		return nil, err
 	}
 	return &remote, nil
@@ -190,6 +228,11 @@ func (c *Client) RemoteUpload(url string, folderID string, headers map[string]st
 // uploadID is used to check only one remote upload status.
 // https://openload.co/api#remoteul-check
 func (c *Client) RemoteUploadStatus(limit int64, uploadID string) (RemoteUploadsStatusResponse, error) {
+	return c.RemoteUploadStatusContext(context.Background(), limit, uploadID)
+}
+
+// RemoteUploadStatusContext is like RemoteUploadStatus but uses ctx for the request.
+func (c *Client) RemoteUploadStatusContext(ctx context.Context, limit int64, uploadID string) (RemoteUploadsStatusResponse, error) {
 	var status RemoteUploadsStatusResponse
 	params := make(map[string]string)
 	if limit == -1 {
@@ -199,7 +242,7 @@ func (c *Client) RemoteUploadStatus(limit int64, uploadID string) (RemoteUploads
 	if uploadID != "" {
 		params["id"] = uploadID
 	}
-	if err := c.get("/remotedl/status", params, &status); err != nil {
+	if err := c.getIdempotent(ctx, "/remotedl/status", params, &status); err != nil {
 		return nil, err
 	}
 	return status, nil
@@ -208,12 +251,17 @@ func (c *Client) RemoteUploadStatus(limit int64, uploadID string) (RemoteUploads
 // ListFolder lists a folder content.
 // https://openload.co/api#file-listfolder
 func (c *Client) ListFolder(folderID string) (*ListFolderResponse, error) {
+	return c.ListFolderContext(context.Background(), folderID)
+}
+
+// ListFolderContext is like ListFolder but uses ctx for the request.
+func (c *Client) ListFolderContext(ctx context.Context, folderID string) (*ListFolderResponse, error) {
 	var list ListFolderResponse
 	params := make(map[string]string)
 	if folderID != "" {
 		params["folder"] = folderID
 	}
-	if err := c.get("/file/listfolder", params, &list); err != nil {
+	if err := c.getIdempotent(ctx, "/file/listfolder", params, &list); err != nil {
 		return nil, err
 	}
 	return &list, nil
@@ -222,18 +270,78 @@ func (c *Client) ListFolder(folderID string) (*ListFolderResponse, error) {
 // RenameFolder renames existing folder.
 // https://openload.co/api#file-renamefolder
 func (c *Client) RenameFolder(folderID string, name string) (RenameFolderResponse, error) {
+	return c.RenameFolderContext(context.Background(), folderID, name)
+}
+
+// RenameFolderContext is like RenameFolder but uses ctx for the request.
+func (c *Client) RenameFolderContext(ctx context.Context, folderID string, name string) (RenameFolderResponse, error) {
 	var renamed RenameFolderResponse
-	if err := c.get("/file/renamefolder", map[string]string{"folder": folderID, "name": name}, &renamed); err != nil {
+	if err := c.get(ctx, "/file/renamefolder", map[string]string{"folder": folderID, "name": name}, &renamed); err != nil {
 		return renamed, err
 	}
 	return renamed, nil
 }
 
+// CreateFolder creates a folder called name inside parentID.
+// parentID is optional pass empty string "" to create it in home folder.
+// https://openload.co/api#file-createfolder
+func (c *Client) CreateFolder(parentID string, name string) (CreateFolderResponse, error) {
+	return c.CreateFolderContext(context.Background(), parentID, name)
+}
+
+// CreateFolderContext is like CreateFolder but uses ctx for the request.
+func (c *Client) CreateFolderContext(ctx context.Context, parentID string, name string) (CreateFolderResponse, error) {
+	var created CreateFolderResponse
+	params := map[string]string{"name": name}
+	if parentID != "" {
+		params["pid"] = parentID
+	}
+	if err := c.get(ctx, "/file/createfolder", params, &created); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// DeleteFolder deletes existing folder.
+// https://openload.co/api#file-deletefolder
+func (c *Client) DeleteFolder(folderID string) (DeleteFolderResponse, error) {
+	return c.DeleteFolderContext(context.Background(), folderID)
+}
+
+// DeleteFolderContext is like DeleteFolder but uses ctx for the request.
+func (c *Client) DeleteFolderContext(ctx context.Context, folderID string) (DeleteFolderResponse, error) {
+	var deleted DeleteFolderResponse
+	if err := c.get(ctx, "/file/deletefolder", map[string]string{"folder": folderID}, &deleted); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// MoveFile moves existing file to folderID.
+// https://openload.co/api#file-move
+func (c *Client) MoveFile(fileID string, folderID string) (MoveFileResponse, error) {
+	return c.MoveFileContext(context.Background(), fileID, folderID)
+}
+
+// MoveFileContext is like MoveFile but uses ctx for the request.
+func (c *Client) MoveFileContext(ctx context.Context, fileID string, folderID string) (MoveFileResponse, error) {
+	var moved MoveFileResponse
+	if err := c.get(ctx, "/file/move", map[string]string{"file": fileID, "folder": folderID}, &moved); err != nil {
+		return moved, err
+	}
+	return moved, nil
+}
+
 // RenameFile renames existing file.
 // https://openload.co/api#file-rename
 func (c *Client) RenameFile(fileID string, name string) (RenameFileResponse, error) {
+	return c.RenameFileContext(context.Background(), fileID, name)
+}
+
+// RenameFileContext is like RenameFile but uses ctx for the request.
+func (c *Client) RenameFileContext(ctx context.Context, fileID string, name string) (RenameFileResponse, error) {
 	var renamed RenameFileResponse
-	if err := c.get("/file/rename", map[string]string{"file": fileID, "name": name}, &renamed); err != nil {
+	if err := c.get(ctx, "/file/rename", map[string]string{"file": fileID, "name": name}, &renamed); err != nil {
 		return renamed, err
 	}
 	return renamed, nil
@@ -242,8 +350,13 @@ func (c *Client) RenameFile(fileID string, name string) (RenameFileResponse, err
 // DeleteFile deletes existing file.
 // https://openload.co/api#file-delete
 func (c *Client) DeleteFile(fileID string) (DeleteFileResponse, error) {
+	return c.DeleteFileContext(context.Background(), fileID)
+}
+
+// DeleteFileContext is like DeleteFile but uses ctx for the request.
+func (c *Client) DeleteFileContext(ctx context.Context, fileID string) (DeleteFileResponse, error) {
 	var deleted DeleteFileResponse
-	if err := c.get("/file/delete", map[string]string{"file": fileID}, &deleted); err != nil {
+	if err := c.get(ctx, "/file/delete", map[string]string{"file": fileID}, &deleted); err != nil {
 		return deleted, err
 	}
 	return deleted, nil
@@ -254,8 +367,13 @@ func (c *Client) DeleteFile(fileID string) (DeleteFileResponse, error) {
 // https://openload.co/account#conversionsettings
 // https://openload.co/api#convertingfiles
 func (c *Client) ConvertFile(fileID string) (ConvertFileResponse, error) {
+	return c.ConvertFileContext(context.Background(), fileID)
+}
+
+// ConvertFileContext is like ConvertFile but uses ctx for the request.
+func (c *Client) ConvertFileContext(ctx context.Context, fileID string) (ConvertFileResponse, error) {
 	var converted ConvertFileResponse
-	if err := c.get("/file/convert", map[string]string{"file": fileID}, &converted); err != nil {
+	if err := c.get(ctx, "/file/convert", map[string]string{"file": fileID}, &converted); err != nil {
 		return converted, err
 	}
 	return converted, nil
@@ -266,12 +384,17 @@ func (c *Client) ConvertFile(fileID string) (ConvertFileResponse, error) {
 // if not specified all running conversion will be returned.
 // https://openload.co/api#file-runningconverts
 func (c *Client) RunningConversions(folderID string) (RunningConversionsResponse, error) {
+	return c.RunningConversionsContext(context.Background(), folderID)
+}
+
+// RunningConversionsContext is like RunningConversions but uses ctx for the request.
+func (c *Client) RunningConversionsContext(ctx context.Context, folderID string) (RunningConversionsResponse, error) {
 	var conversions RunningConversionsResponse
 	params := make(map[string]string)
 	if folderID != "" {
 		params["folder"] = folderID
 	}
-	if err := c.get("/file/runningconverts", params, &conversions); err != nil {
+	if err := c.getIdempotent(ctx, "/file/runningconverts", params, &conversions); err != nil {
 		return nil, err
 	}
 	return conversions, nil
@@ -281,8 +404,13 @@ func (c *Client) RunningConversions(folderID string) (RunningConversionsResponse
 // Usually it should be used with media fileID (movie, ...)
 // https://openload.co/api#file-splash
 func (c *Client) SplashImage(fileID string) (SplashImageResponse, error) {
+	return c.SplashImageContext(context.Background(), fileID)
+}
+
+// SplashImageContext is like SplashImage but uses ctx for the request.
+func (c *Client) SplashImageContext(ctx context.Context, fileID string) (SplashImageResponse, error) {
 	var image SplashImageResponse
-	if err := c.get("/file/getsplash", map[string]string{"file": fileID}, &image); err != nil {
+	if err := c.getIdempotent(ctx, "/file/getsplash", map[string]string{"file": fileID}, &image); err != nil {
 		return "", err
 	}
 	return image, nil
@@ -308,41 +436,50 @@ func (c *Client) getAPIURL(p string, q map[string]string) (string, error) {
 	return u.String(), nil
 }
 
-func processResponse(response io.Reader, result interface{}) error {
-	var data map[string]*json.RawMessage
-	var status int
-	var msg string
-
-	err := json.NewDecoder(response).Decode(&data)
+func (c *Client) newRequest(ctx context.Context, method, u string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequest(method, u, body)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = json.Unmarshal(*data["status"], &status)
-	if err != nil {
-		return err
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
 	}
-	err = json.Unmarshal(*data["msg"], &msg)
+	return request.WithContext(ctx), nil
+}
+
+func (c *Client) get(ctx context.Context, p string, q map[string]string, result interface{}) error {
+	u, err := c.getAPIURL(p, q)
 	if err != nil {
 		return err
 	}
-	err = checkStatus(status, msg)
-	if err != nil {
-		return err
+	l := c.limiters[endpointGroup(p)]
+	if l != nil {
+		if err := l.wait(ctx); err != nil {
+			return err
+		}
 	}
-	return json.Unmarshal(*data["result"], &result)
-}
-
-func (c *Client) get(p string, q map[string]string, result interface{}) error {
-	u, err := c.getAPIURL(p, q)
+	request, err := c.newRequest(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return err
 	}
-	response, err := http.Get(u)
+	response, err := c.httpClient.Do(request)
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
-	return processResponse(response.Body, &result)
+	err = processResponse(response, &result)
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.Endpoint = p
+		apiErr.Params = redactParams(c.login, q)
+	}
+	if l != nil {
+		l.observe(err)
+	}
+	c.invalidate(p)
+	if err == nil {
+		localize(reflect.ValueOf(result), c.location)
+	}
+	return err
 }
 
 // New creates new openload client an returns a reference.
@@ -350,14 +487,22 @@ func (c *Client) get(p string, q map[string]string, result interface{}) error {
 // httpClient might be passed to override default httpClient
 // example override reqeusts timeout.
 // https://golang.org/pkg/net/http/#Client
-func New(login, key string, httpClient *http.Client) *Client {
+// opts are optional and applied in order (WithBaseURL, WithHTTPClient, ...).
+func New(login, key string, httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return &Client{
+	c := &Client{
 		login:      login,
 		key:        key,
-		api:        buildAPIURL(),
+		baseURL:    apiBaseURL,
+		apiVersion: apiVersion,
 		httpClient: httpClient,
+		location:   time.UTC,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.api = joinAPIURL(c.baseURL, c.apiVersion)
+	return c
 }
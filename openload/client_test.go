@@ -1,6 +1,9 @@
 package openload
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +14,24 @@ func c() *Client {
 	return New("LOGIN", "KEY", nil)
 }
 
+// apiServer is an httptest server embedded by the test fixtures
+// that need a live api rather than gock mocks.
+type apiServer struct {
+	*httptest.Server
+}
+
+// newAPIServer starts a server running handler, it is closed when t ends.
+func newAPIServer(t *testing.T, handler http.Handler) apiServer {
+	s := apiServer{httptest.NewServer(handler)}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// client returns a client calling the api served by s.
+func (s apiServer) client(opts ...Option) *Client {
+	return New("LOGIN", "KEY", nil, append([]Option{WithBaseURL(s.URL)}, opts...)...)
+}
+
 func TestAccountInfo(t *testing.T) {
 	defer gock.Off()
 
@@ -24,9 +45,9 @@ func TestAccountInfo(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, "extuserid", info.Extid)
 	assert.EqualValues(t, "jeff@openload.io", info.Email)
-	assert.EqualValues(t, "2015-01-09 23:59:54", info.SignupAt)
+	assert.EqualValues(t, "2015-01-09 23:59:54", info.SignupAt.String())
 	assert.EqualValues(t, -1, info.StorageLeft)
-	assert.EqualValues(t, "32922117680", info.StorageUsed)
+	assert.EqualValues(t, 32922117680, info.StorageUsed)
 	assert.EqualValues(t, -1, info.Traffic.Left)
 	assert.EqualValues(t, 0, info.Traffic.Used24H)
 	assert.EqualValues(t, 0, info.Balance)
@@ -48,7 +69,7 @@ func TestDownloadTicket(t *testing.T) {
 	assert.EqualValues(t, 140, ticket.CaptchaW)
 	assert.EqualValues(t, 70, ticket.CaptchaH)
 	assert.EqualValues(t, 10, ticket.WaitTime)
-	assert.EqualValues(t, "2015-08-23 18:20:13", ticket.ValidUntil)
+	assert.EqualValues(t, "2015-08-23 18:20:13", ticket.ValidUntil.String())
 }
 
 func TestDownloadLink(t *testing.T) {
@@ -66,7 +87,7 @@ func TestDownloadLink(t *testing.T) {
 	assert.EqualValues(t, 12345, link.Size)
 	assert.EqualValues(t, "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12", link.Sha1)
 	assert.EqualValues(t, "plain/text", link.ContentType)
-	assert.EqualValues(t, "2011-01-26 13:33:37", link.UploadAt)
+	assert.EqualValues(t, "2011-01-26 13:33:37", link.UploadAt.String())
 	assert.EqualValues(t, "https://abvzps.example.com/dl/l/4spxX_-cSO4/The+quick+brown+fox.txt", link.URL)
 	assert.EqualValues(t, "4spxX_-cSO4", link.Token)
 }
@@ -126,7 +147,7 @@ func TestUploadLink(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.EqualValues(t, "https://13abc37.example.com/ul/fCgaPthr_ys", uploadLink.URL)
-	assert.EqualValues(t, "2015-01-09 00:02:50", uploadLink.ValidUntil)
+	assert.EqualValues(t, "2015-01-09 00:02:50", uploadLink.ValidUntil.String())
 }
 
 func TestRemoteUpload(t *testing.T) {
@@ -160,6 +181,13 @@ func TestRemoteUploadStatus(t *testing.T) {
 	assert.Contains(t, remote, "22")
 	assert.Contains(t, remote, "20")
 	assert.Contains(t, remote, "3")
+	assert.EqualValues(t, "22", remote["22"].ID)
+	assert.EqualValues(t, 823997062, remote["22"].BytesLoaded)
+	assert.EqualValues(t, 1073741824, remote["22"].BytesTotal)
+	assert.EqualValues(t, "", remote["22"].URL)
+	assert.EqualValues(t, 0, remote["24"].BytesTotal)
+	assert.EqualValues(t, "ANAaeBZus-Q", remote["20"].Extid)
+	assert.EqualValues(t, "https://openload.co/f/ANAaeBZus-Q", remote["20"].URL)
 }
 
 func TestListFolder(t *testing.T) {
@@ -175,6 +203,15 @@ func TestListFolder(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, listed.Folders, 6)
 	assert.Len(t, listed.Files, 2)
+	assert.EqualValues(t, 5114011, listed.Files[0].Size)
+	assert.EqualValues(t, 48, listed.Files[0].DownloadCount)
+
+	folder := listed.Folders[2]
+	assert.EqualValues(t, "test", folder.String())
+	file := listed.Files[1]
+	assert.EqualValues(t, "https://openload.co/f/AYgHe95d1E4/Sintel.2010.1080p.mkv.mp4", file.URL())
+	assert.EqualValues(t, 1116102098, file.SizeBytes())
+	assert.EqualValues(t, ConversionOK, file.Cstatus)
 }
 
 func TestRenameFolder(t *testing.T) {
@@ -191,6 +228,53 @@ func TestRenameFolder(t *testing.T) {
 	assert.EqualValues(t, true, renamed)
 }
 
+func TestCreateFolder(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/createfolder").
+		MatchParam("name", "my new folder").
+		MatchParam("pid", "5").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":true}`)
+
+	created, err := c().CreateFolder("5", "my new folder")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, created)
+}
+
+func TestDeleteFolder(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/deletefolder").
+		MatchParam("folder", "5").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":true}`)
+
+	deleted, err := c().DeleteFolder("5")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, deleted)
+}
+
+func TestMoveFile(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/move").
+		MatchParam("file", "UPPjeAk--30").
+		MatchParam("folder", "5").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":true}`)
+
+	moved, err := c().MoveFile("UPPjeAk--30", "5")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, moved)
+}
+
 func TestRenameFile(t *testing.T) {
 	defer gock.Off()
 
@@ -248,9 +332,9 @@ func TestRunningConversions(t *testing.T) {
 	assert.EqualValues(t, "Geysir.AVI", conversions[0].Name)
 	assert.EqualValues(t, "3565411", conversions[0].ID)
 	assert.EqualValues(t, "pending", conversions[0].Status)
-	assert.EqualValues(t, "2015-08-23 19:41:40", conversions[0].LastUpdate)
+	assert.EqualValues(t, "2015-08-23 19:41:40", conversions[0].LastUpdate.String())
 	assert.EqualValues(t, 0.32, conversions[0].Progress)
-	assert.EqualValues(t, "0", conversions[0].Retries)
+	assert.EqualValues(t, 0, conversions[0].Retries)
 	assert.EqualValues(t, "https://openload.co/f/f02JFG293J8/Geysir.AVI", conversions[0].Link)
 	assert.EqualValues(t, "f02JFG293J8", conversions[0].Linkextid)
 }
@@ -268,3 +352,20 @@ func TestSplashImage(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, "https://openload.co/splash/AYgHe95d1E4/zt8uSEmk56s.jpg", splash)
 }
+
+func TestAccountInfoContextCanceled(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/account/info").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"extid":"extuserid"}}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	info, err := c().AccountInfoContext(ctx)
+
+	assert.Nil(t, info)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+}
@@ -0,0 +1,304 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrCaptchaRequired is returned by Download when a ticket requires
+// a captcha and no CaptchaSolver is configured.
+var ErrCaptchaRequired = errors.New("openload: download ticket requires a captcha")
+
+// errRangeIgnored is returned when a server answers a range request
+// with the whole content.
+var errRangeIgnored = errors.New("openload: server ignored range request")
+
+// statusError is returned when a download url answers with an unexpected
+// http status, usually because the link expired.
+type statusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("openload: download request failed: %s", e.Status)
+}
+
+// maxTicketAttempts caps how many tickets Download requests
+// when tickets expire before their wait time is over.
+const maxTicketAttempts = 3
+
+// DownloadOptions holds the optional parameters of a download.
+type DownloadOptions struct {
+	// Progress is called as content is received, optional.
+	Progress ProgressFunc
+	// CaptchaSolver solves ticket captchas, optional
+	// nil fails with ErrCaptchaRequired when a captcha is needed.
+	CaptchaSolver CaptchaSolver
+	// MaxResumes is how many times DownloadToFile resumes a broken
+	// transfer with a fresh link before giving up, 0 disables it.
+	// With Segments a broken range is first resumed with the same link,
+	// the unfinished ranges are resumed together with a fresh link
+	// when the link is rejected.
+	MaxResumes int
+	// Segments splits DownloadToFile into that many byte ranges fetched
+	// concurrently, values lower than 2 use a single stream.
+	// It falls back to a single stream when the server does not support
+	// ranges or when resuming an existing part file, a part file left
+	// preallocated by an interrupted segmented download is started over.
+	Segments int
+}
+
+// Download runs the whole download flow of fileID: it requests a ticket,
+// solves its captcha if any, waits for the ticket wait time,
+// requests a download link
+// and streams the file content to w.
+// https://openload.co/api#download-ticket
+// https://openload.co/api#download-getlink
+func (c *Client) Download(ctx context.Context, fileID string, w io.Writer, opts DownloadOptions) (*DownloadLinkResponse, error) {
+	link, err := c.downloadLink(ctx, fileID, opts.CaptchaSolver)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.openRange(ctx, link.URL, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if opts.Progress != nil {
+		total := response.ContentLength
+		if total < 0 {
+			total = int64(link.Size)
+		}
+		w = newProgressWriter(w, total, opts.Progress)
+	}
+	if _, err := io.Copy(w, response.Body); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// DownloadToFile is like Download but writes the content to the file name.
+// Content is written to name.part first, an existing name.part is resumed
+// using a Range request. Once complete the content is verified against
+// the sha1 of the link and the file is renamed to name.
+// A failed download leaves name.part in place for the next attempt
+// unless its content does not match the expected sha1.
+func (c *Client) DownloadToFile(ctx context.Context, fileID string, name string, opts DownloadOptions) (*DownloadLinkResponse, error) {
+	part := name + ".part"
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	link, err := c.downloadToFile(ctx, fileID, file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if link.Sha1 != "" {
+		sum, err := sha1File(part)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(sum, link.Sha1) {
+			os.Remove(part)
+			return nil, &ChecksumError{Expected: link.Sha1, Actual: sum}
+		}
+	}
+	if err := os.Rename(part, name); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// downloadToFile appends the content of fileID to file
+// resuming the transfer with fresh links when it breaks.
+// A file left preallocated by an interrupted segmented download
+// is started over.
+func (c *Client) downloadToFile(ctx context.Context, fileID string, file *os.File, opts DownloadOptions) (*DownloadLinkResponse, error) {
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file.Name() + segmentsSuffix); err == nil {
+		if offset, err = restart(file); err != nil {
+			return nil, err
+		}
+		if err := removeMarker(file); err != nil {
+			return nil, err
+		}
+	}
+	var progress *progressWriter
+	if opts.Progress != nil {
+		progress = newProgressWriter(file, 0, opts.Progress)
+	}
+
+	var segments []*segment
+	for resumes := 0; ; resumes++ {
+		link, err := c.downloadLink(ctx, fileID, opts.CaptchaSolver)
+		if err != nil {
+			return nil, err
+		}
+		size := int64(link.Size)
+		if segments == nil && size > 0 && offset > size {
+			// Stale part file of another content, start over.
+			if offset, err = restart(file); err != nil {
+				return nil, err
+			}
+		}
+		if segments == nil && size > 0 && offset == size {
+			return link, nil
+		}
+
+		if opts.Segments > 1 && size > 0 && (segments != nil || offset == 0) {
+			if ok, _ := c.supportsRanges(ctx, link.URL, size); ok {
+				if segments == nil {
+					if segments, err = preallocate(file, size, opts.Segments, progress); err != nil {
+						return nil, err
+					}
+				}
+				err = c.fetchSegments(ctx, link.URL, file, segments, progress)
+				if err == nil {
+					return link, nil
+				}
+				if ctx.Err() != nil || resumes >= opts.MaxResumes {
+					return nil, err
+				}
+				continue
+			}
+			if segments != nil {
+				// The new link does not serve ranges, start over sequentially.
+				segments = nil
+				if offset, err = restart(file); err != nil {
+					return nil, err
+				}
+				if err := removeMarker(file); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		offset, err = c.fetchToFile(ctx, link.URL, file, offset, size, progress)
+		if err == nil {
+			return link, nil
+		}
+		if ctx.Err() != nil || resumes >= opts.MaxResumes {
+			return nil, err
+		}
+	}
+}
+
+// fetchToFile writes url content from offset to file
+// and returns the offset reached.
+func (c *Client) fetchToFile(ctx context.Context, url string, file *os.File, offset, size int64, progress *progressWriter) (int64, error) {
+	response, err := c.openRange(ctx, url, offset, -1)
+	if err != nil {
+		return offset, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusOK && offset > 0 {
+		// The server ignored the range, start over.
+		if offset, err = restart(file); err != nil {
+			return offset, err
+		}
+	}
+
+	var w io.Writer = file
+	if progress != nil {
+		progress.mu.Lock()
+		progress.sent, progress.total = offset, size
+		progress.mu.Unlock()
+		w = progress
+	}
+	n, err := io.Copy(w, response.Body)
+	return offset + n, err
+}
+
+// openRange requests url content from start to end inclusive,
+// a negative end means until the end of the content.
+// The response status is either 200 or 206 when the server honoured the range.
+func (c *Client) openRange(ctx context.Context, url string, start, end int64) (*http.Response, error) {
+	request, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case end >= 0:
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	case start > 0:
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusPartialContent {
+		response.Body.Close()
+		return nil, &statusError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+	return response, nil
+}
+
+// restart truncates file and rewinds it.
+func restart(file *os.File) (int64, error) {
+	if err := file.Truncate(0); err != nil {
+		return 0, err
+	}
+	return file.Seek(0, io.SeekStart)
+}
+
+// sha1File returns the hex encoded sha1 of the file name.
+func sha1File(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return sha1Seeker(f)
+}
+
+// downloadLink requests a ticket, solves its captcha, waits for it
+// and exchanges it for a link, a new ticket is requested when the previous
+// one expired or its captcha was not solved correctly.
+func (c *Client) downloadLink(ctx context.Context, fileID string, solver CaptchaSolver) (*DownloadLinkResponse, error) {
+	for attempt := 1; ; attempt++ {
+		ticket, err := c.DownloadTicketContext(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		var captchaResponse string
+		if ticket.CaptchaURL != "" {
+			if solver == nil {
+				return nil, ErrCaptchaRequired
+			}
+			if captchaResponse, err = c.solveCaptcha(ctx, solver, ticket); err != nil {
+				return nil, err
+			}
+		}
+		if err := sleep(ctx, time.Duration(ticket.WaitTime)*time.Second); err != nil {
+			return nil, err
+		}
+		if ticket.Expired() {
+			if attempt < maxTicketAttempts {
+				continue
+			}
+			return nil, fmt.Errorf("openload: download ticket of %s expired at %s", fileID, ticket.ValidUntil)
+		}
+		link, err := c.DownloadLinkContext(ctx, fileID, ticket.Ticket, captchaResponse)
+		if errors.Is(err, ErrCaptchaFailed) && attempt < maxTicketAttempts {
+			continue
+		}
+		return link, err
+	}
+}
@@ -0,0 +1,236 @@
+package openload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// downloadServer serves the download api endpoints and the file content.
+type downloadServer struct {
+	apiServer
+	content    string
+	captchaURL string
+	validUntil string
+	tickets    int
+	missing    bool
+	// captcha is the expected captcha response, dl fails when it differs.
+	captcha string
+	// sha1 is the sha1 reported by the download link.
+	sha1 string
+	// breakAfter aborts the first content response requested
+	// with breakRange after that many bytes.
+	breakAfter int
+	breakRange string
+	// noRanges ignores Range headers.
+	noRanges bool
+	// expired is the number of content requests answered with 410.
+	expired int
+	// expireRange answers the first request of that range with 410.
+	expireRange string
+	mu          sync.Mutex
+	ranges      []string
+}
+
+// abortWriter aborts the response after n bytes.
+type abortWriter struct {
+	http.ResponseWriter
+	n int
+}
+
+func (w *abortWriter) Write(p []byte) (int, error) {
+	if len(p) >= w.n {
+		w.ResponseWriter.Write(p[:w.n])
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.n -= len(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func newDownloadServer(t *testing.T, content string) *downloadServer {
+	s := &downloadServer{content: content, captchaURL: "false", validUntil: "2099-01-01 00:00:00"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/file/dlticket", func(w http.ResponseWriter, r *http.Request) {
+		s.tickets++
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"ticket":"72fA-_Lq8Ak~~1440353112~n~~0~nXtN3RI-nsEa28Iq","captcha_url":%s,"captcha_w":140,"captcha_h":70,"wait_time":0,"valid_until":%q}}`, s.captchaURL, s.validUntil)
+	})
+	mux.HandleFunc("/1/file/dl", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("captcha_response") != s.captcha {
+			fmt.Fprint(w, `{"status":403,"msg":"Captcha not solved correctly","result":null}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"name":"fox.txt","size":%d,"sha1":%q,"content_type":"plain/text","upload_at":"2011-01-26 13:33:37","url":"%s/dl/fox.txt","token":"4spxX_-cSO4"}}`, len(s.content), s.sha1, s.URL)
+	})
+	mux.HandleFunc("/captcha.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PNG"))
+	})
+	mux.HandleFunc("/dl/fox.txt", func(w http.ResponseWriter, r *http.Request) {
+		if s.missing {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		if s.expired > 0 {
+			s.expired--
+			s.mu.Unlock()
+			http.Error(w, "link expired", http.StatusGone)
+			return
+		}
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		if s.expireRange != "" && r.Header.Get("Range") == s.expireRange {
+			s.expireRange = ""
+			s.mu.Unlock()
+			http.Error(w, "link expired", http.StatusGone)
+			return
+		}
+		if s.breakAfter > 0 && r.Header.Get("Range") == s.breakRange {
+			w = &abortWriter{ResponseWriter: w, n: s.breakAfter}
+			s.breakAfter = 0
+		}
+		s.mu.Unlock()
+		if s.noRanges {
+			w.Write([]byte(s.content))
+			return
+		}
+		http.ServeContent(w, r, "fox.txt", time.Time{}, strings.NewReader(s.content))
+	})
+	s.apiServer = newAPIServer(t, mux)
+	return s
+}
+
+func TestDownload(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+
+	var buf bytes.Buffer
+	var last Progress
+	link, err := s.client().Download(context.Background(), "72fA-_Lq8Ak", &buf, DownloadOptions{
+		Progress: func(p Progress) { last = p },
+	})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fox.txt", link.Name)
+	assert.EqualValues(t, "The quick brown fox", buf.String())
+	assert.EqualValues(t, len("The quick brown fox"), last.BytesSent)
+	assert.EqualValues(t, len("The quick brown fox"), last.Total)
+}
+
+func TestDownloadCaptchaRequired(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.captchaURL = `"https://openload.co/dlcaptcha/b92eY_nfjV4.png"`
+
+	_, err := s.client().Download(context.Background(), "72fA-_Lq8Ak", ioutil.Discard, DownloadOptions{})
+
+	assert.True(t, errors.Is(err, ErrCaptchaRequired))
+}
+
+func TestDownloadTicketExpired(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.validUntil = "2015-08-23 18:20:13"
+
+	_, err := s.client().Download(context.Background(), "72fA-_Lq8Ak", ioutil.Discard, DownloadOptions{})
+
+	assert.Error(t, err)
+	assert.EqualValues(t, maxTicketAttempts, s.tickets)
+}
+
+func TestDownloadToFile(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "The quick brown fox", string(data))
+}
+
+func TestDownloadToFileFailure(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.missing = true
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Error(t, err)
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadToFileResume(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.sha1 = fmt.Sprintf("%x", sha1.Sum([]byte("The quick brown fox")))
+	name := filepath.Join(t.TempDir(), "fox.txt")
+	assert.Nil(t, ioutil.WriteFile(name+".part", []byte("The quick"), 0644))
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bytes=9-"}, s.ranges)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "The quick brown fox", string(data))
+	_, err = os.Stat(name + ".part")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadToFileBrokenTransfer(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.breakAfter = 4
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	var last Progress
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{
+		MaxResumes: 1,
+		Progress:   func(p Progress) { last = p },
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", "bytes=4-"}, s.ranges)
+	assert.EqualValues(t, 2, s.tickets)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "The quick brown fox", string(data))
+	assert.EqualValues(t, len("The quick brown fox"), last.BytesSent)
+	assert.EqualValues(t, len("The quick brown fox"), last.Total)
+}
+
+func TestDownloadToFileBrokenTransferNoResume(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.breakAfter = 4
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Error(t, err)
+	data, err := ioutil.ReadFile(name + ".part")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "The ", string(data))
+}
+
+func TestDownloadToFileChecksumMismatch(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.sha1 = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	_, err = os.Stat(name + ".part")
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,111 @@
+package openload
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+// https://openload.co/api#response-codes
+var (
+	ErrBadRequest                 = errors.New("openload: bad request")
+	ErrPermissionDenied           = errors.New("openload: permission denied")
+	ErrNotFound                   = errors.New("openload: file not found")
+	ErrUnavailableForLegalReasons = errors.New("openload: unavailable for legal reasons")
+	ErrBandwidthExceeded          = errors.New("openload: bandwidth usage exceeded")
+	ErrCaptchaFailed              = errors.New("openload: captcha not solved")
+)
+
+// ErrChecksumMismatch is matched by *ChecksumError through errors.Is.
+var ErrChecksumMismatch = errors.New("openload: sha1 checksum mismatch")
+
+// statusBandwidthExceeded is the non standard status openload returns
+// when the bandwidth limit is reached.
+const statusBandwidthExceeded = 509
+
+// redacted replaces the api key in APIError params.
+const redacted = "REDACTED"
+
+// APIError represents a non 200 status returned by openload api.
+type APIError struct {
+	// Status is the status field of the response envelope.
+	Status int
+	// Msg is the msg field of the response envelope.
+	Msg string
+	// Endpoint is the api path requested e.g. /file/info.
+	Endpoint string
+	// Params holds the request query parameters, the api key is redacted.
+	Params map[string]string
+	// RetryAfter is the Retry-After header of the response if any.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Endpoint == "" {
+		return fmt.Sprintf("openload: status %d: %s", e.Status, e.Msg)
+	}
+	return fmt.Sprintf("openload: %s: status %d: %s", e.Endpoint, e.Status, e.Msg)
+}
+
+// Is reports whether e matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.Status == http.StatusBadRequest
+	case ErrPermissionDenied:
+		return e.Status == http.StatusForbidden
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrUnavailableForLegalReasons:
+		return e.Status == http.StatusUnavailableForLegalReasons
+	case ErrBandwidthExceeded:
+		return e.Status == statusBandwidthExceeded
+	case ErrCaptchaFailed:
+		return e.Status == http.StatusForbidden && strings.Contains(strings.ToLower(e.Msg), "captcha")
+	}
+	return false
+}
+
+func redactParams(login string, q map[string]string) map[string]string {
+	params := map[string]string{"login": login, "key": redacted}
+	for k, v := range q {
+		params[k] = v
+	}
+	return params
+}
+
+// ChecksumError represents a sha1 mismatch between local and remote content.
+type ChecksumError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("openload: sha1 checksum mismatch: expected %s got %s", e.Expected, e.Actual)
+}
+
+// Is reports whether target is ErrChecksumMismatch.
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// ErrRemoteUploadFailed is matched by *RemoteUploadFailedError through errors.Is.
+var ErrRemoteUploadFailed = errors.New("openload: remote upload failed")
+
+// RemoteUploadFailedError represents a remote upload whose status turned to a failure state.
+type RemoteUploadFailedError struct {
+	ID     string
+	Status RemoteUploadStatusResponse
+}
+
+func (e *RemoteUploadFailedError) Error() string {
+	return fmt.Sprintf("openload: remote upload %s of %s failed: status %s", e.ID, e.Status.Remoteurl, e.Status.Status)
+}
+
+// Is reports whether target is ErrRemoteUploadFailed.
+func (e *RemoteUploadFailedError) Is(target error) bool {
+	return target == ErrRemoteUploadFailed
+}
@@ -0,0 +1,58 @@
+package openload
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestAPIError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/dl").
+		Reply(200).
+		BodyString(`{"status":403,"msg":"Captcha not solved correctly","result":null}`)
+
+	link, err := c().DownloadLink("<FILE_ID>", "ticket", "wrong")
+
+	assert.Nil(t, link)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.EqualValues(t, 403, apiErr.Status)
+	assert.EqualValues(t, "Captcha not solved correctly", apiErr.Msg)
+	assert.EqualValues(t, "/file/dl", apiErr.Endpoint)
+	assert.EqualValues(t, "LOGIN", apiErr.Params["login"])
+	assert.EqualValues(t, "REDACTED", apiErr.Params["key"])
+	assert.EqualValues(t, "<FILE_ID>", apiErr.Params["file"])
+	assert.True(t, errors.Is(err, ErrPermissionDenied))
+	assert.True(t, errors.Is(err, ErrCaptchaFailed))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.NotContains(t, err.Error(), "KEY")
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+	}{
+		{400, ErrBadRequest},
+		{403, ErrPermissionDenied},
+		{404, ErrNotFound},
+		{451, ErrUnavailableForLegalReasons},
+		{509, ErrBandwidthExceeded},
+	}
+	for _, tt := range tests {
+		err := error(&APIError{Status: tt.status, Msg: "msg"})
+		assert.True(t, errors.Is(err, tt.target), "status %d", tt.status)
+		assert.False(t, errors.Is(err, ErrCaptchaFailed), "status %d", tt.status)
+	}
+}
+
+func TestAPIErrorIsCaptchaFailed(t *testing.T) {
+	assert.True(t, errors.Is(&APIError{Status: 403, Msg: "Captcha not solved correctly"}, ErrCaptchaFailed))
+	assert.False(t, errors.Is(&APIError{Status: 404, Msg: "captcha not found"}, ErrCaptchaFailed))
+	assert.False(t, errors.Is(&APIError{Status: 500, Msg: "captcha service down"}, ErrCaptchaFailed))
+}
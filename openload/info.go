@@ -0,0 +1,136 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultFilesInfoBatchSize is the number of ids FilesInfo sends per request.
+const defaultFilesInfoBatchSize = 50
+
+// defaultFilesInfoConcurrency is the number of FilesInfo requests run concurrently.
+const defaultFilesInfoConcurrency = 4
+
+// WithFilesInfoBatching sets how many ids FilesInfo sends per request
+// and how many of these requests run concurrently.
+func WithFilesInfoBatching(size, concurrency int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.infoBatchSize = size
+		}
+		if concurrency > 0 {
+			c.infoConcurrency = concurrency
+		}
+	}
+}
+
+// FilesInfoError reports the ids FilesInfo could not get info for,
+// either because they were missing from the response or because
+// the request of their batch failed. Files listed with a failed status
+// are returned as info, see FileStatus.IsFailed.
+type FilesInfoError struct {
+	// Errors maps each failed id to its error.
+	Errors map[string]error
+}
+
+func (e *FilesInfoError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) == 1 {
+		return fmt.Sprintf("openload: file info %s: %v", ids[0], e.Errors[ids[0]])
+	}
+	return fmt.Sprintf("openload: file info failed for %d files, %s: %v", len(ids), ids[0], e.Errors[ids[0]])
+}
+
+// Is reports whether any of the per id errors matches target.
+func (e *FilesInfoError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// filesInfo requests info for filesID in batches, see FilesInfo.
+func (c *Client) filesInfo(ctx context.Context, filesID []string) (FilesInfoResponse, error) {
+	batches := batchIDs(filesID, c.infoBatchSize)
+	results := make([]FilesInfoResponse, len(batches))
+	errs := make([]error, len(batches))
+	n := c.infoConcurrency
+	if n <= 0 {
+		n = defaultFilesInfoConcurrency
+	}
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = c.getIdempotent(ctx, "/file/info", map[string]string{"file": strings.Join(batches[i], ",")}, &results[i])
+		}(i)
+	}
+	wg.Wait()
+
+	infos := FilesInfoResponse{}
+	failed := map[string]error{}
+	succeeded := false
+	for i, batch := range batches {
+		if errs[i] != nil {
+			for _, id := range batch {
+				failed[id] = errs[i]
+			}
+			continue
+		}
+		succeeded = true
+		for id, info := range results[i] {
+			infos[id] = info
+		}
+		for _, id := range batch {
+			if _, ok := infos[id]; !ok {
+				failed[id] = fmt.Errorf("missing from response: %w", ErrNotFound)
+			}
+		}
+	}
+	if !succeeded && len(batches) > 0 {
+		return nil, errs[0]
+	}
+	if len(failed) > 0 {
+		return infos, &FilesInfoError{Errors: failed}
+	}
+	return infos, nil
+}
+
+// batchIDs splits ids, without duplicates, into batches of at most size ids.
+func batchIDs(ids []string, size int) [][]string {
+	if size <= 0 {
+		size = defaultFilesInfoBatchSize
+	}
+	seen := make(map[string]bool, len(ids))
+	var batches [][]string
+	var batch []string
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		batch = append(batch, id)
+		if len(batch) == size {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
@@ -0,0 +1,107 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// infoServer serves /1/file/info, ids in missing are left out of the
+// response and batches containing an id in broken fail.
+type infoServer struct {
+	apiServer
+	mu      sync.Mutex
+	missing map[string]bool
+	broken  map[string]bool
+	batches [][]string
+}
+
+func newInfoServer(t *testing.T) *infoServer {
+	s := &infoServer{missing: map[string]bool{}, broken: map[string]bool{}}
+	s.apiServer = newAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("file"), ",")
+		s.mu.Lock()
+		s.batches = append(s.batches, ids)
+		s.mu.Unlock()
+		var infos []string
+		for _, id := range ids {
+			if s.broken[id] {
+				fmt.Fprint(w, `{"status":403,"msg":"permission denied","result":null}`)
+				return
+			}
+			if !s.missing[id] {
+				infos = append(infos, fmt.Sprintf(`"%s":{"id":"%s","status":200,"name":"%s.txt","size":"1"}`, id, id, id))
+			}
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{%s}}`, strings.Join(infos, ","))
+	}))
+	return s
+}
+
+func testIDs(n int) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestFilesInfoBatches(t *testing.T) {
+	s := newInfoServer(t)
+
+	infos, err := s.client(WithFilesInfoBatching(3, 2)).FilesInfo(append(testIDs(8), "0", "1"))
+
+	assert.Nil(t, err)
+	assert.Len(t, infos, 8)
+	assert.EqualValues(t, "7.txt", infos["7"].Name)
+	assert.Len(t, s.batches, 3)
+	for _, batch := range s.batches {
+		assert.True(t, len(batch) <= 3)
+	}
+}
+
+func TestFilesInfoMissing(t *testing.T) {
+	s := newInfoServer(t)
+	s.missing["4"] = true
+	s.broken["7"] = true
+
+	infos, err := s.client(WithFilesInfoBatching(3, 2)).FilesInfo(testIDs(8))
+
+	assert.Len(t, infos, 5)
+	var infoErr *FilesInfoError
+	assert.True(t, errors.As(err, &infoErr))
+	assert.Len(t, infoErr.Errors, 3)
+	assert.True(t, errors.Is(infoErr.Errors["4"], ErrNotFound))
+	assert.True(t, errors.Is(infoErr.Errors["6"], ErrPermissionDenied))
+	assert.True(t, errors.Is(infoErr.Errors["7"], ErrPermissionDenied))
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestFilesInfoAllFailed(t *testing.T) {
+	s := newInfoServer(t)
+	s.broken["0"] = true
+
+	infos, err := s.client(WithFilesInfoBatching(3, 2)).FilesInfo(testIDs(2))
+
+	assert.Nil(t, infos)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.EqualValues(t, 403, apiErr.Status)
+}
+
+func TestFileInfoMissing(t *testing.T) {
+	s := newInfoServer(t)
+	s.missing["0"] = true
+
+	info, err := s.client(WithFilesInfoBatching(3, 2)).FileInfoContext(context.Background(), "0")
+
+	assert.Nil(t, info)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
@@ -2,47 +2,47 @@ package openload
 
 // AccountInfoResponse represents account info response.
 type AccountInfoResponse struct {
-	Extid       string      `json:"extid"`
-	Email       string      `json:"email"`
-	SignupAt    string      `json:"signup_at"`
-	StorageLeft int         `json:"storage_left"`
-	StorageUsed interface{} `json:"storage_used"`
+	Extid       string    `json:"extid"`
+	Email       string    `json:"email"`
+	SignupAt    Timestamp `json:"signup_at"`
+	StorageLeft int       `json:"storage_left"`
+	StorageUsed FlexInt64 `json:"storage_used"`
 	Traffic     struct {
 		Left    int `json:"left"`
 		Used24H int `json:"used_24h"`
 	} `json:"traffic"`
-	Balance interface{} `json:"balance"`
+	Balance FlexFloat64 `json:"balance"`
 }
 
 // DownloadTicketResponse represents download ticket response.
 type DownloadTicketResponse struct {
-	Ticket     string      `json:"ticket"`
-	CaptchaURL interface{} `json:"captcha_url"`
-	CaptchaW   interface{} `json:"captcha_w"`
-	CaptchaH   interface{} `json:"captcha_h"`
-	WaitTime   int         `json:"wait_time"`
-	ValidUntil string      `json:"valid_until"`
+	Ticket     string     `json:"ticket"`
+	CaptchaURL FlexString `json:"captcha_url"`
+	CaptchaW   FlexInt64  `json:"captcha_w"`
+	CaptchaH   FlexInt64  `json:"captcha_h"`
+	WaitTime   int        `json:"wait_time"`
+	ValidUntil Timestamp  `json:"valid_until"`
 }
 
 // DownloadLinkResponse represents download link response.
 type DownloadLinkResponse struct {
-	Name        string      `json:"name"`
-	Size        interface{} `json:"size"`
-	Sha1        string      `json:"sha1"`
-	ContentType string      `json:"content_type"`
-	UploadAt    string      `json:"upload_at"`
-	URL         string      `json:"url"`
-	Token       string      `json:"token"`
+	Name        string    `json:"name"`
+	Size        FlexInt64 `json:"size"`
+	Sha1        string    `json:"sha1"`
+	ContentType string    `json:"content_type"`
+	UploadAt    Timestamp `json:"upload_at"`
+	URL         string    `json:"url"`
+	Token       string    `json:"token"`
 }
 
 // FileInfoResponse represents single file info response.
 type FileInfoResponse struct {
-	ID          string      `json:"id"`
-	Status      int         `json:"status"`
-	Name        interface{} `json:"name"`
-	Size        interface{} `json:"size"`
-	Sha1        interface{} `json:"sha1"`
-	ContentType interface{} `json:"content_type"`
+	ID          string     `json:"id"`
+	Status      FileStatus `json:"status"`
+	Name        FlexString `json:"name"`
+	Size        FlexInt64  `json:"size"`
+	Sha1        FlexString `json:"sha1"`
+	ContentType FlexString `json:"content_type"`
 }
 
 // FilesInfoResponse represents multiple files info response.
@@ -50,18 +50,18 @@ type FilesInfoResponse map[string]FileInfoResponse
 
 // UploadURLResponse represents upload url response.
 type UploadURLResponse struct {
-	URL        string `json:"url"`
-	ValidUntil string `json:"valid_until"`
+	URL        string    `json:"url"`
+	ValidUntil Timestamp `json:"valid_until"`
 }
 
 // UploadResponse represents upload response.
 type UploadResponse struct {
-	ContentType string `json:"content_type"`
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Sha1        string `json:"sha1"`
-	Size        string `json:"size"`
-	URL         string `json:"url"`
+	ContentType string    `json:"content_type"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Sha1        string    `json:"sha1"`
+	Size        FlexInt64 `json:"size"`
+	URL         string    `json:"url"`
 }
 
 // RemoteUploadResponse represents remote upload response.
@@ -72,45 +72,75 @@ type RemoteUploadResponse struct {
 
 // RemoteUploadStatusResponse represents single remote upload status.
 type RemoteUploadStatusResponse struct {
-	ID          interface{} `json:"id"`
-	Remoteurl   string      `json:"remoteurl"`
-	Status      string      `json:"status"`
-	BytesLoaded interface{} `json:"bytes_loaded"`
-	BytesTotal  interface{} `json:"bytes_total"`
-	Folderid    string      `json:"folderid"`
-	Added       string      `json:"added"`
-	LastUpdate  string      `json:"last_update"`
-	Extid       interface{} `json:"extid"`
-	URL         interface{} `json:"url"`
+	ID          FlexString        `json:"id"`
+	Remoteurl   string            `json:"remoteurl"`
+	Status      RemoteUploadState `json:"status"`
+	BytesLoaded FlexInt64         `json:"bytes_loaded"`
+	BytesTotal  FlexInt64         `json:"bytes_total"`
+	Folderid    string            `json:"folderid"`
+	Added       Timestamp         `json:"added"`
+	LastUpdate  Timestamp         `json:"last_update"`
+	Extid       FlexString        `json:"extid"`
+	URL         FlexString        `json:"url"`
 }
 
 // RemoteUploadsStatusResponse represents all remote uploads status.
 type RemoteUploadsStatusResponse map[string]RemoteUploadStatusResponse
 
+// Folder represents a folder entry of ListFolderResponse.
+type Folder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// String returns the folder name.
+func (f Folder) String() string {
+	return f.Name
+}
+
+// File represents a file entry of ListFolderResponse.
+type File struct {
+	Name          string          `json:"name"`
+	Sha1          string          `json:"sha1"`
+	Folderid      string          `json:"folderid"`
+	UploadAt      Timestamp       `json:"upload_at"`
+	Status        string          `json:"status"`
+	Size          FlexInt64       `json:"size"`
+	ContentType   string          `json:"content_type"`
+	DownloadCount FlexInt64       `json:"download_count"`
+	Cstatus       ConversionState `json:"cstatus"`
+	Link          string          `json:"link"`
+	Linkextid     string          `json:"linkextid"`
+}
+
+// URL returns the file page url.
+func (f File) URL() string {
+	return f.Link
+}
+
+// SizeBytes returns the file size in bytes.
+func (f File) SizeBytes() int64 {
+	return int64(f.Size)
+}
+
 // ListFolderResponse represents list folder response.
 type ListFolderResponse struct {
-	Folders []struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-	} `json:"folders"`
-	Files []struct {
-		Name          string `json:"name"`
-		Sha1          string `json:"sha1"`
-		Folderid      string `json:"folderid"`
-		UploadAt      string `json:"upload_at"`
-		Status        string `json:"status"`
-		Size          string `json:"size"`
-		ContentType   string `json:"content_type"`
-		DownloadCount string `json:"download_count"`
-		Cstatus       string `json:"cstatus"`
-		Link          string `json:"link"`
-		Linkextid     string `json:"linkextid"`
-	}
+	Folders []Folder `json:"folders"`
+	Files   []File   `json:"files"`
 }
 
 // RenameFolderResponse represents rename folder response either true or false.
 type RenameFolderResponse bool
 
+// CreateFolderResponse represents create folder response either true or false.
+type CreateFolderResponse bool
+
+// DeleteFolderResponse represents delete folder response either true or false.
+type DeleteFolderResponse bool
+
+// MoveFileResponse represents move file response either true or false.
+type MoveFileResponse bool
+
 // RenameFileResponse represents rename file response either true or false.
 type RenameFileResponse bool
 
@@ -120,17 +150,20 @@ type DeleteFileResponse bool
 // ConvertFileResponse represents conver file response either true or false.
 type ConvertFileResponse bool
 
-// RunningConversionsResponse represents pending conversions response.
-type RunningConversionsResponse []struct {
-	Name       string  `json:"name"`
-	ID         string  `json:"id"`
-	Status     string  `json:"status"`
-	LastUpdate string  `json:"last_update"`
-	Progress   float64 `json:"progress"`
-	Retries    string  `json:"retries"`
-	Link       string  `json:"link"`
-	Linkextid  string  `json:"linkextid"`
+// Conversion represents a single pending conversion.
+type Conversion struct {
+	Name       string          `json:"name"`
+	ID         string          `json:"id"`
+	Status     ConversionState `json:"status"`
+	LastUpdate Timestamp       `json:"last_update"`
+	Progress   float64         `json:"progress"`
+	Retries    FlexInt64       `json:"retries"`
+	Link       string          `json:"link"`
+	Linkextid  string          `json:"linkextid"`
 }
 
+// RunningConversionsResponse represents pending conversions response.
+type RunningConversionsResponse []Conversion
+
 // SplashImageResponse represents splash image response.
 type SplashImageResponse string
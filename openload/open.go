@@ -0,0 +1,142 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Open returns a reader of the remote content of fileID.
+// Content is fetched lazily with Range requests starting at the current
+// offset, seeking only drops the running request. The download link is
+// requested by Open and renewed transparently when it expires.
+// ctx governs every request issued by the returned reader.
+func (c *Client) Open(ctx context.Context, fileID string, opts DownloadOptions) (io.ReadSeekCloser, error) {
+	link, err := c.downloadLink(ctx, fileID, opts.CaptchaSolver)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteFile{
+		c:      c,
+		ctx:    ctx,
+		fileID: fileID,
+		solver: opts.CaptchaSolver,
+		link:   link,
+		size:   int64(link.Size),
+	}, nil
+}
+
+var (
+	errClosed         = errors.New("openload: read of closed file")
+	errNegativeOffset = errors.New("openload: negative offset")
+	errUnknownSize    = errors.New("openload: file size unknown")
+	errInvalidWhence  = errors.New("openload: invalid whence")
+)
+
+// remoteFile implements io.ReadSeekCloser over Range requests.
+type remoteFile struct {
+	c      *Client
+	ctx    context.Context
+	fileID string
+	solver CaptchaSolver
+	link   *DownloadLinkResponse
+	size   int64
+	offset int64
+	body   io.ReadCloser
+	closed bool
+}
+
+func (f *remoteFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, errClosed
+	}
+	if f.size > 0 && f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for attempt := 0; ; attempt++ {
+		if f.body == nil {
+			if err := f.open(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := f.body.Read(p)
+		f.offset += int64(n)
+		if err == io.EOF || err == nil || n > 0 {
+			return n, err
+		}
+		// The transfer broke, reopen once from the current offset.
+		f.drop()
+		if attempt > 0 || f.ctx.Err() != nil {
+			return 0, err
+		}
+	}
+}
+
+// open issues a Range request from the current offset
+// renewing the download link once if the request fails.
+func (f *remoteFile) open() error {
+	response, err := f.c.openRange(f.ctx, f.link.URL, f.offset, -1)
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		if f.link, err = f.c.downloadLink(f.ctx, f.fileID, f.solver); err != nil {
+			return err
+		}
+		response, err = f.c.openRange(f.ctx, f.link.URL, f.offset, -1)
+	}
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == http.StatusOK && f.offset > 0 {
+		response.Body.Close()
+		return errRangeIgnored
+	}
+	f.body = response.Body
+	return nil
+}
+
+func (f *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, errClosed
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		if f.size <= 0 {
+			return 0, errUnknownSize
+		}
+		offset += f.size
+	case io.SeekStart:
+	default:
+		return 0, errInvalidWhence
+	}
+	if offset < 0 {
+		return 0, errNegativeOffset
+	}
+	if offset != f.offset {
+		f.drop()
+		f.offset = offset
+	}
+	return offset, nil
+}
+
+func (f *remoteFile) Close() error {
+	if f.closed {
+		return errClosed
+	}
+	f.closed = true
+	f.drop()
+	return nil
+}
+
+// drop closes the running request if any.
+func (f *remoteFile) drop() {
+	if f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+}
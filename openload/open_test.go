@@ -0,0 +1,74 @@
+package openload
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+
+	f, err := s.client().Open(context.Background(), "72fA-_Lq8Ak", DownloadOptions{})
+	assert.Nil(t, err)
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "The quick brown fox", string(data))
+
+	offset, err := f.Seek(4, io.SeekStart)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, offset)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(f, buf)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "quick", string(buf))
+
+	offset, err = f.Seek(-3, io.SeekEnd)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 16, offset)
+	data, err = ioutil.ReadAll(f)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fox", string(data))
+
+	assert.Equal(t, []string{"", "bytes=4-", "bytes=16-"}, s.ranges)
+}
+
+func TestOpenLazy(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+
+	f, err := s.client().Open(context.Background(), "72fA-_Lq8Ak", DownloadOptions{})
+	assert.Nil(t, err)
+	_, err = f.Seek(10, io.SeekStart)
+	assert.Nil(t, err)
+	offset, err := f.Seek(-2, io.SeekCurrent)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8, offset)
+	_, err = f.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+	assert.Nil(t, f.Close())
+
+	assert.Empty(t, s.ranges)
+	_, err = f.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestOpenRenewsLink(t *testing.T) {
+	s := newDownloadServer(t, "The quick brown fox")
+	s.expired = 1
+
+	f, err := s.client().Open(context.Background(), "72fA-_Lq8Ak", DownloadOptions{})
+	assert.Nil(t, err)
+	defer f.Close()
+	_, err = f.Seek(4, io.SeekStart)
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(f)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "quick brown fox", string(data))
+	assert.EqualValues(t, 2, s.tickets)
+}
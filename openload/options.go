@@ -0,0 +1,42 @@
+package openload
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures a Client created by New.
+type Option func(*Client)
+
+// WithBaseURL overrides the api base URL (https://api.openload.co)
+// useful to point the client at a mirror, a proxy or a local stand-in.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIVersion overrides the api version appended to the base URL.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = strings.Trim(version, "/")
+	}
+}
+
+// WithHTTPClient overrides the http client used for every request
+// it takes precedence over the httpClient passed to New.
+// nil is ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
@@ -0,0 +1,46 @@
+package openload
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestWithBaseURL(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://mirror.local/v2").
+		Get("/account/info").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"extid":"extuserid"}}`)
+
+	client := New("LOGIN", "KEY", nil, WithBaseURL("http://mirror.local/"), WithAPIVersion("v2"))
+	info, err := client.AccountInfo()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "extuserid", info.Extid)
+	assert.True(t, gock.IsDone())
+}
+
+func TestWithHTTPClientAndUserAgent(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/account/info").
+		MatchHeader("User-Agent", "gopenload-test").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"extid":"extuserid"}}`)
+
+	httpClient := &http.Client{}
+	gock.InterceptClient(httpClient)
+	defer gock.RestoreClient(httpClient)
+
+	client := New("LOGIN", "KEY", nil, WithHTTPClient(httpClient), WithUserAgent("gopenload-test"))
+	info, err := client.AccountInfo()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "extuserid", info.Extid)
+	assert.True(t, client.httpClient == httpClient)
+}
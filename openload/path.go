@@ -0,0 +1,277 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithListingCache caches folder listings used by path based methods
+// (ResolvePath, MkdirAll, ...) for ttl. The cache is dropped whenever
+// the client modifies files or folders.
+func WithListingCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.listings = &listingCache{ttl: ttl, entries: make(map[string]cachedListing)}
+	}
+}
+
+type cachedListing struct {
+	list    *ListFolderResponse
+	expires time.Time
+}
+
+// listingCache is a ttl cache of folder listings keyed by folder id.
+// gen counts the clears so that a listing fetched before a clear
+// is not cached after it.
+type listingCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	gen     uint64
+	entries map[string]cachedListing
+}
+
+func (lc *listingCache) get(folderID string) *ListFolderResponse {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	e, ok := lc.entries[folderID]
+	if !ok || time.Now().After(e.expires) {
+		delete(lc.entries, folderID)
+		return nil
+	}
+	return e.list
+}
+
+// generation returns the generation to pass to put for a listing fetched now.
+func (lc *listingCache) generation() uint64 {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.gen
+}
+
+// put caches list unless the cache was cleared since generation gen.
+func (lc *listingCache) put(folderID string, list *ListFolderResponse, gen uint64) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if gen != lc.gen {
+		return
+	}
+	lc.entries[folderID] = cachedListing{list: list, expires: time.Now().Add(lc.ttl)}
+}
+
+func (lc *listingCache) clear() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.gen++
+	lc.entries = make(map[string]cachedListing)
+}
+
+// mutatingEndpoints modify files or folders and invalidate the listing cache.
+var mutatingEndpoints = map[string]bool{
+	"/file/createfolder": true,
+	"/file/deletefolder": true,
+	"/file/renamefolder": true,
+	"/file/move":         true,
+	"/file/rename":       true,
+	"/file/delete":       true,
+	"/file/convert":      true,
+	"/remotedl/add":      true,
+}
+
+// invalidate drops cached listings after a call to endpoint p.
+func (c *Client) invalidate(p string) {
+	if c.listings != nil && mutatingEndpoints[p] {
+		c.listings.clear()
+	}
+}
+
+// listFolder is ListFolderContext going through the listing cache.
+func (c *Client) listFolder(ctx context.Context, folderID string) (*ListFolderResponse, error) {
+	if c.listings == nil {
+		return c.ListFolderContext(ctx, folderID)
+	}
+	if list := c.listings.get(folderID); list != nil {
+		return list, nil
+	}
+	gen := c.listings.generation()
+	list, err := c.ListFolderContext(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	c.listings.put(folderID, list, gen)
+	return list, nil
+}
+
+// splitPath returns the non empty segments of a slash separated path.
+func splitPath(p string) []string {
+	var segments []string
+	for _, s := range strings.Split(p, "/") {
+		if s != "" && s != "." {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// findFolder returns the id of the folder called name inside parentID
+// or an empty string if there is none.
+func (c *Client) findFolder(ctx context.Context, parentID string, name string) (string, error) {
+	list, err := c.listFolder(ctx, parentID)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range list.Folders {
+		if f.Name == name {
+			return f.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// MkdirAll creates every missing folder of p starting at home folder
+// and returns the id of the last one, "/" returns the home folder id "".
+func (c *Client) MkdirAll(ctx context.Context, p string) (string, error) {
+	parentID := ""
+	for _, name := range splitPath(p) {
+		id, err := c.findFolder(ctx, parentID, name)
+		if err != nil {
+			return "", err
+		}
+		if id == "" {
+			if _, err := c.CreateFolderContext(ctx, parentID, name); err != nil {
+				return "", err
+			}
+			if id, err = c.findFolder(ctx, parentID, name); err != nil {
+				return "", err
+			}
+			if id == "" {
+				return "", fmt.Errorf("openload: folder %s was created but can not be found", name)
+			}
+		}
+		parentID = id
+	}
+	return parentID, nil
+}
+
+// ResolvePath resolves a slash separated path starting at home folder
+// into the folder or file it designates, "/" is the home folder.
+// Errors match ErrNotFound when a segment does not exist.
+// The returned entry Folder.ID or File.Linkextid is the id to pass
+// to the id based methods.
+func (c *Client) ResolvePath(ctx context.Context, p string) (WalkEntry, error) {
+	entry := WalkEntry{Path: "/", Folder: &Folder{}}
+	segments := splitPath(p)
+	for i, name := range segments {
+		if !entry.IsDir() {
+			return WalkEntry{}, fmt.Errorf("openload: %s: not a folder: %w", entry.Path, ErrNotFound)
+		}
+		list, err := c.listFolder(ctx, entry.Folder.ID)
+		if err != nil {
+			return WalkEntry{}, err
+		}
+		next, ok := lookup(list, name)
+		if !ok {
+			return WalkEntry{}, fmt.Errorf("openload: %s: %w", "/"+path.Join(segments[:i+1]...), ErrNotFound)
+		}
+		next.Path = path.Join(entry.Path, name)
+		entry = next
+	}
+	return entry, nil
+}
+
+// lookup finds the folder or file called name in list, folders first.
+func lookup(list *ListFolderResponse, name string) (WalkEntry, bool) {
+	for i := range list.Folders {
+		if list.Folders[i].Name == name {
+			folder := list.Folders[i]
+			return WalkEntry{Folder: &folder}, true
+		}
+	}
+	for i := range list.Files {
+		if list.Files[i].Name == name {
+			file := list.Files[i]
+			return WalkEntry{File: &file}, true
+		}
+	}
+	return WalkEntry{}, false
+}
+
+var errNotFolder = errors.New("openload: not a folder")
+
+var errHomeFolder = errors.New("openload: the home folder can not be renamed or deleted")
+
+// resolveFolder resolves p and checks it is a folder.
+func (c *Client) resolveFolder(ctx context.Context, p string) (string, error) {
+	entry, err := c.ResolvePath(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if !entry.IsDir() {
+		return "", fmt.Errorf("%w: %s", errNotFolder, p)
+	}
+	return entry.Folder.ID, nil
+}
+
+// resolveNonRoot resolves p and checks it is not the home folder.
+func (c *Client) resolveNonRoot(ctx context.Context, p string) (WalkEntry, error) {
+	if len(splitPath(p)) == 0 {
+		return WalkEntry{}, errHomeFolder
+	}
+	return c.ResolvePath(ctx, p)
+}
+
+// ListPath is like ListFolder but takes a folder path.
+func (c *Client) ListPath(ctx context.Context, p string) (*ListFolderResponse, error) {
+	folderID, err := c.resolveFolder(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListFolderContext(ctx, folderID)
+}
+
+// UploadToPath uploads the local file name to the existing folder dir.
+func (c *Client) UploadToPath(ctx context.Context, name string, dir string, opts UploadOptions) (*UploadResponse, error) {
+	folderID, err := c.resolveFolder(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	opts.FolderID = folderID
+	return c.UploadFile(ctx, f, opts)
+}
+
+// RenamePath renames the file or folder at p to name, p can not be "/".
+func (c *Client) RenamePath(ctx context.Context, p string, name string) (bool, error) {
+	entry, err := c.resolveNonRoot(ctx, p)
+	if err != nil {
+		return false, err
+	}
+	if entry.IsDir() {
+		renamed, err := c.RenameFolderContext(ctx, entry.Folder.ID, name)
+		return bool(renamed), err
+	}
+	renamed, err := c.RenameFileContext(ctx, entry.File.Linkextid, name)
+	return bool(renamed), err
+}
+
+// DeletePath deletes the file or folder at p, p can not be "/".
+func (c *Client) DeletePath(ctx context.Context, p string) (bool, error) {
+	entry, err := c.resolveNonRoot(ctx, p)
+	if err != nil {
+		return false, err
+	}
+	if entry.IsDir() {
+		deleted, err := c.DeleteFolderContext(ctx, entry.Folder.ID)
+		return bool(deleted), err
+	}
+	deleted, err := c.DeleteFileContext(ctx, entry.File.Linkextid)
+	return bool(deleted), err
+}
@@ -0,0 +1,235 @@
+package openload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// treeServer is an in memory folder tree serving the folder endpoints,
+// the home folder id is "".
+type treeServer struct {
+	apiServer
+	mu       sync.Mutex
+	nextID   int
+	parents  map[string]string
+	listings map[string]*ListFolderResponse
+	calls    map[string]int
+}
+
+func newTreeServer(t *testing.T) *treeServer {
+	s := &treeServer{
+		nextID:   100,
+		parents:  map[string]string{},
+		listings: map[string]*ListFolderResponse{"": {}},
+		calls:    map[string]int{},
+	}
+	reply := func(w http.ResponseWriter, result interface{}) {
+		data, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":%s}`, data)
+	}
+	notFound := func(w http.ResponseWriter) {
+		fmt.Fprint(w, `{"status":404,"msg":"not found","result":null}`)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/file/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		q := r.URL.Query()
+		s.calls[r.URL.Path[len("/1"):]]++
+		switch r.URL.Path {
+		case "/1/file/listfolder":
+			list, ok := s.listings[q.Get("folder")]
+			if !ok {
+				notFound(w)
+				return
+			}
+			reply(w, list)
+		case "/1/file/createfolder":
+			if _, ok := s.listings[q.Get("pid")]; !ok {
+				notFound(w)
+				return
+			}
+			s.mkdir(q.Get("pid"), q.Get("name"))
+			reply(w, true)
+		case "/1/file/rename", "/1/file/delete", "/1/file/renamefolder", "/1/file/deletefolder":
+			reply(w, true)
+		default:
+			notFound(w)
+		}
+	})
+	s.apiServer = newAPIServer(t, mux)
+	return s
+}
+
+// mkdir adds a folder called name to parentID and returns its id.
+func (s *treeServer) mkdir(parentID, name string) string {
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.listings[parentID].Folders = append(s.listings[parentID].Folders, Folder{ID: id, Name: name})
+	s.listings[id] = &ListFolderResponse{}
+	s.parents[id] = parentID
+	return id
+}
+
+// touch adds a file called name to folderID and returns its id.
+func (s *treeServer) touch(folderID, name string) string {
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	s.listings[folderID].Files = append(s.listings[folderID].Files, File{Name: name, Linkextid: id, Folderid: folderID})
+	return id
+}
+
+func TestMkdirAll(t *testing.T) {
+	s := newTreeServer(t)
+	movies := s.mkdir("", "movies")
+
+	id, err := s.client().MkdirAll(context.Background(), "/movies/2019/summer/")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, s.calls["/file/createfolder"])
+	summer := s.listings[s.listings[movies].Folders[0].ID].Folders[0]
+	assert.EqualValues(t, "summer", summer.Name)
+	assert.EqualValues(t, summer.ID, id)
+
+	again, err := s.client().MkdirAll(context.Background(), "movies/2019/summer")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, id, again)
+	assert.EqualValues(t, 2, s.calls["/file/createfolder"])
+}
+
+func TestMkdirAllRoot(t *testing.T) {
+	s := newTreeServer(t)
+
+	id, err := s.client().MkdirAll(context.Background(), "/")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", id)
+	assert.Empty(t, s.calls)
+}
+
+func TestResolvePath(t *testing.T) {
+	s := newTreeServer(t)
+	movies := s.mkdir("", "movies")
+	y2019 := s.mkdir(movies, "2019")
+	x := s.touch(y2019, "x.mp4")
+	client := s.client()
+
+	entry, err := client.ResolvePath(context.Background(), "/movies/2019/x.mp4")
+	assert.Nil(t, err)
+	assert.False(t, entry.IsDir())
+	assert.EqualValues(t, x, entry.File.Linkextid)
+	assert.EqualValues(t, "/movies/2019/x.mp4", entry.Path)
+
+	entry, err = client.ResolvePath(context.Background(), "movies/2019/")
+	assert.Nil(t, err)
+	assert.True(t, entry.IsDir())
+	assert.EqualValues(t, y2019, entry.Folder.ID)
+
+	entry, err = client.ResolvePath(context.Background(), "/")
+	assert.Nil(t, err)
+	assert.True(t, entry.IsDir())
+	assert.EqualValues(t, "", entry.Folder.ID)
+
+	_, err = client.ResolvePath(context.Background(), "/movies/2020/x.mp4")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), "/movies/2020")
+
+	_, err = client.ResolvePath(context.Background(), "/movies/2019/x.mp4/y")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestListingCache(t *testing.T) {
+	s := newTreeServer(t)
+	movies := s.mkdir("", "movies")
+	s.touch(movies, "x.mp4")
+	client := s.client(WithListingCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ResolvePath(context.Background(), "/movies/x.mp4")
+		assert.Nil(t, err)
+	}
+	assert.EqualValues(t, 2, s.calls["/file/listfolder"])
+
+	deleted, err := client.DeletePath(context.Background(), "/movies/x.mp4")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assert.EqualValues(t, 1, s.calls["/file/delete"])
+
+	_, err = client.ResolvePath(context.Background(), "/movies/x.mp4")
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, s.calls["/file/listfolder"])
+}
+
+func TestListingCacheStalePut(t *testing.T) {
+	lc := &listingCache{ttl: time.Minute, entries: make(map[string]cachedListing)}
+	gen := lc.generation()
+	lc.clear()
+	lc.put("5", &ListFolderResponse{}, gen)
+	assert.Nil(t, lc.get("5"))
+
+	lc.put("5", &ListFolderResponse{}, lc.generation())
+	assert.NotNil(t, lc.get("5"))
+}
+
+func TestRootPathRejected(t *testing.T) {
+	s := newTreeServer(t)
+	client := s.client()
+
+	_, err := client.RenamePath(context.Background(), "/", "home")
+	assert.Equal(t, errHomeFolder, err)
+	_, err = client.DeletePath(context.Background(), "")
+	assert.Equal(t, errHomeFolder, err)
+	assert.EqualValues(t, 0, s.calls["/file/renamefolder"])
+	assert.EqualValues(t, 0, s.calls["/file/deletefolder"])
+}
+
+func TestListingCacheExpires(t *testing.T) {
+	s := newTreeServer(t)
+	s.mkdir("", "movies")
+	client := s.client(WithListingCache(time.Nanosecond))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.ResolvePath(context.Background(), "/movies")
+		assert.Nil(t, err)
+	}
+	assert.EqualValues(t, 2, s.calls["/file/listfolder"])
+}
+
+func TestPathWrappers(t *testing.T) {
+	s := newTreeServer(t)
+	movies := s.mkdir("", "movies")
+	s.touch(movies, "x.mp4")
+	client := s.client()
+
+	list, err := client.ListPath(context.Background(), "/movies")
+	assert.Nil(t, err)
+	assert.Len(t, list.Files, 1)
+
+	_, err = client.ListPath(context.Background(), "/movies/x.mp4")
+	assert.Error(t, err)
+
+	renamed, err := client.RenamePath(context.Background(), "/movies/x.mp4", "y.mp4")
+	assert.Nil(t, err)
+	assert.True(t, renamed)
+	assert.EqualValues(t, 1, s.calls["/file/rename"])
+
+	renamed, err = client.RenamePath(context.Background(), "/movies", "films")
+	assert.Nil(t, err)
+	assert.True(t, renamed)
+	assert.EqualValues(t, 1, s.calls["/file/renamefolder"])
+
+	deleted, err := client.DeletePath(context.Background(), "/movies")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assert.EqualValues(t, 1, s.calls["/file/deletefolder"])
+}
@@ -0,0 +1,67 @@
+package openload
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Progress describes the state of a running transfer.
+type Progress struct {
+	// BytesSent is the number of content bytes transferred so far.
+	BytesSent int64
+	// Total is the content size, 0 if unknown.
+	Total int64
+	// Rate is the average transfer rate in bytes per second.
+	Rate float64
+	// ETA is the estimated remaining time, 0 if unknown.
+	ETA time.Duration
+}
+
+// ProgressFunc receives progress updates of a transfer, calls are never
+// concurrent and BytesSent never decreases within a transfer,
+// it should return quickly.
+type ProgressFunc func(Progress)
+
+// progressWriter counts bytes written through it and reports them to fn.
+type progressWriter struct {
+	w     io.Writer
+	fn    ProgressFunc
+	mu    sync.Mutex
+	start time.Time
+	sent  int64
+	total int64
+}
+
+func newProgressWriter(w io.Writer, total int64, fn ProgressFunc) *progressWriter {
+	return &progressWriter{w: w, fn: fn, start: time.Now(), total: total}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.add(int64(n))
+	}
+	return n, err
+}
+
+// add records n transferred bytes and reports the new state,
+// fn is called with the lock held so that concurrent writers
+// report an ordered stream.
+func (p *progressWriter) add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent += n
+	p.fn(p.progress(time.Now()))
+}
+
+func (p *progressWriter) progress(now time.Time) Progress {
+	progress := Progress{BytesSent: p.sent, Total: p.total}
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		progress.Rate = float64(p.sent) / elapsed
+	}
+	if p.total > p.sent && progress.Rate > 0 {
+		progress.ETA = time.Duration(float64(p.total-p.sent) / progress.Rate * float64(time.Second))
+	}
+	return progress
+}
@@ -0,0 +1,31 @@
+package openload
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	p := newProgressWriter(ioutil.Discard, 1000, func(Progress) {})
+	p.sent = 250
+
+	progress := p.progress(p.start.Add(time.Second))
+
+	assert.EqualValues(t, 250, progress.BytesSent)
+	assert.EqualValues(t, 1000, progress.Total)
+	assert.EqualValues(t, 250, progress.Rate)
+	assert.EqualValues(t, 3*time.Second, progress.ETA)
+}
+
+func TestProgressUnknownTotal(t *testing.T) {
+	p := newProgressWriter(ioutil.Discard, 0, func(Progress) {})
+	p.sent = 250
+
+	progress := p.progress(p.start.Add(time.Second))
+
+	assert.EqualValues(t, 250, progress.Rate)
+	assert.EqualValues(t, 0, progress.ETA)
+}
@@ -0,0 +1,223 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueClosed is returned by RemoteUploadQueue.Add after Close.
+var ErrQueueClosed = errors.New("openload: remote upload queue closed")
+
+// defaultQueueConcurrency is the number of remote uploads a queue keeps active.
+const defaultQueueConcurrency = 5
+
+// maxQueuePollFailures is how many times in a row polling an active
+// remote upload may fail before the queue gives up on it.
+const maxQueuePollFailures = 5
+
+// RemoteUploadItem is a remote upload added to a RemoteUploadQueue.
+type RemoteUploadItem struct {
+	// URL is the source url of the remote upload.
+	URL string
+	// FolderID is optional, the file is uploaded to the home folder if empty.
+	FolderID string
+	// Headers are optional additional http headers sent to URL.
+	Headers map[string]string
+}
+
+// RemoteUploadResult is the outcome of a RemoteUploadItem.
+type RemoteUploadResult struct {
+	Item RemoteUploadItem
+	// File is the final status holding the file id (Extid) and URL,
+	// nil if the remote upload failed.
+	File *RemoteUploadStatusResponse
+	// Attempts is the number of times the remote upload was added.
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// RemoteUploadQueueOptions configures a RemoteUploadQueue.
+type RemoteUploadQueueOptions struct {
+	// Concurrency is the maximum number of remote uploads active
+	// on the server at once (Default: 5).
+	Concurrency int
+	// Retries is the number of times a remote upload whose status turned
+	// to a failure state is added again, other errors are not retried.
+	Retries int
+	// Wait configures the polling of active remote uploads, its Progress
+	// is ignored in favor of Progress below.
+	Wait RemoteUploadWaitOptions
+	// Progress is optional, it receives the progress of every active item
+	// and may be called concurrently.
+	Progress func(item RemoteUploadItem, progress Progress)
+}
+
+// RemoteUploadQueue runs many remote uploads keeping at most
+// Concurrency of them active, see NewRemoteUploadQueue.
+type RemoteUploadQueue struct {
+	client  *Client
+	ctx     context.Context
+	opts    RemoteUploadQueueOptions
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []RemoteUploadItem
+	closed  bool
+	results chan RemoteUploadResult
+	wg      sync.WaitGroup
+}
+
+// NewRemoteUploadQueue returns a running queue, items are added with Add
+// and their results read from Results which is closed once the queue
+// is closed and every item is done:
+//
+//	q := client.NewRemoteUploadQueue(ctx, openload.RemoteUploadQueueOptions{Retries: 2})
+//	go func() {
+//		for _, url := range urls {
+//			q.Add(openload.RemoteUploadItem{URL: url})
+//		}
+//		q.Close()
+//	}()
+//	for r := range q.Results() {
+//		fmt.Println(r.Item.URL, r.File, r.Err)
+//	}
+//
+// Results must be drained, every added item gets exactly one result.
+// Items left when ctx is done get ctx.Err() as result.
+// https://openload.co/api#remoteul-add
+func (c *Client) NewRemoteUploadQueue(ctx context.Context, opts RemoteUploadQueueOptions) *RemoteUploadQueue {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultQueueConcurrency
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	q := &RemoteUploadQueue{
+		client:  c,
+		ctx:     ctx,
+		opts:    opts,
+		results: make(chan RemoteUploadResult),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.cond.Broadcast()
+			q.mu.Unlock()
+		case <-done:
+		}
+	}()
+	for i := 0; i < opts.Concurrency; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	go func() {
+		q.wg.Wait()
+		close(done)
+		close(q.results)
+	}()
+	return q
+}
+
+// Add queues items, it never blocks. Items are rejected with
+// ErrQueueClosed after Close and with ctx.Err() once ctx is done.
+func (q *RemoteUploadQueue) Add(items ...RemoteUploadItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if err := q.ctx.Err(); err != nil {
+		return err
+	}
+	q.pending = append(q.pending, items...)
+	q.cond.Broadcast()
+	return nil
+}
+
+// Close tells the queue no more items will be added.
+func (q *RemoteUploadQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+// Results returns the stream of results, one per added item.
+func (q *RemoteUploadQueue) Results() <-chan RemoteUploadResult {
+	return q.results
+}
+
+// next returns the next pending item, false once the queue is closed and empty.
+func (q *RemoteUploadQueue) next() (RemoteUploadItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending) == 0 {
+		if q.closed || q.ctx.Err() != nil {
+			return RemoteUploadItem{}, false
+		}
+		q.cond.Wait()
+	}
+	item := q.pending[0]
+	q.pending = q.pending[1:]
+	return item, true
+}
+
+func (q *RemoteUploadQueue) work() {
+	defer q.wg.Done()
+	for {
+		item, ok := q.next()
+		if !ok {
+			return
+		}
+		q.results <- q.run(item)
+	}
+}
+
+// run adds item as a remote upload and waits for it, adding it again
+// when the remote upload fails.
+func (q *RemoteUploadQueue) run(item RemoteUploadItem) RemoteUploadResult {
+	result := RemoteUploadResult{Item: item}
+	wait := q.opts.Wait
+	wait.Progress = nil
+	if q.opts.Progress != nil {
+		wait.Progress = func(p Progress) { q.opts.Progress(item, p) }
+	}
+	defaults := wait.withDefaults()
+	backoff := RetryPolicy{MinBackoff: defaults.MinInterval, MaxBackoff: defaults.MaxInterval}
+	for {
+		if err := q.ctx.Err(); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Attempts++
+		result.File, result.Err = q.attempt(item, wait, backoff)
+		if !errors.Is(result.Err, ErrRemoteUploadFailed) || result.Attempts > q.opts.Retries {
+			return result
+		}
+		if err := sleep(q.ctx, backoff.backoff(result.Attempts)); err != nil {
+			return result
+		}
+	}
+}
+
+// attempt adds item as a remote upload and waits for it, polling errors
+// keep watching the same remote upload so that it is never added twice.
+func (q *RemoteUploadQueue) attempt(item RemoteUploadItem, wait RemoteUploadWaitOptions, backoff RetryPolicy) (*RemoteUploadStatusResponse, error) {
+	remote, err := q.client.RemoteUploadContext(q.ctx, item.URL, item.FolderID, item.Headers)
+	if err != nil {
+		return nil, err
+	}
+	for failures := 1; ; failures++ {
+		file, err := q.client.WaitRemoteUpload(q.ctx, remote.ID, wait)
+		if err == nil || errors.Is(err, ErrRemoteUploadFailed) || q.ctx.Err() != nil || failures >= maxQueuePollFailures {
+			return file, err
+		}
+		if err := sleep(q.ctx, backoff.backoff(failures)); err != nil {
+			return nil, err
+		}
+	}
+}
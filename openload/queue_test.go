@@ -0,0 +1,182 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// queueServer serves /1/remotedl/add and /1/remotedl/status, a remote upload
+// finishes on its second poll unless its url contains "fail" and it is
+// among the first failures attempts of that url, the first flaky polls fail.
+type queueServer struct {
+	apiServer
+	mu        sync.Mutex
+	failures  int
+	flaky     int
+	nextID    int
+	uploads   map[string]string
+	polls     map[string]int
+	attempts  map[string]int
+	params    map[string]map[string]string
+	active    int
+	maxActive int
+}
+
+func newQueueServer(t *testing.T) *queueServer {
+	s := &queueServer{
+		uploads:  map[string]string{},
+		polls:    map[string]int{},
+		attempts: map[string]int{},
+		params:   map[string]map[string]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/remotedl/add", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		url := q.Get("url")
+		s.uploads[id] = url
+		s.attempts[url]++
+		s.params[url] = map[string]string{"folder": q.Get("folder"), "headers": q.Get("headers")}
+		s.active++
+		if s.active > s.maxActive {
+			s.maxActive = s.active
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"id":"%s","folderid":"%s"}}`, id, q.Get("folder"))
+	})
+	mux.HandleFunc("/1/remotedl/status", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.flaky > 0 {
+			s.flaky--
+			fmt.Fprint(w, `{"status":500,"msg":"internal error","result":null}`)
+			return
+		}
+		url := s.uploads[id]
+		s.polls[id]++
+		state := "downloading"
+		if s.polls[id] >= 2 {
+			state = "finished"
+			if strings.Contains(url, "fail") && s.attempts[url] <= s.failures {
+				state = "error"
+			}
+			s.active--
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"%s":{"id":"%s","remoteurl":"%s","status":"%s","extid":"ext%s","url":"https://openload.co/f/ext%s"}}}`, id, id, url, state, id, id)
+	})
+	s.apiServer = newAPIServer(t, mux)
+	return s
+}
+
+func queueOptions(concurrency, retries int) RemoteUploadQueueOptions {
+	return RemoteUploadQueueOptions{
+		Concurrency: concurrency,
+		Retries:     retries,
+		Wait:        RemoteUploadWaitOptions{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond},
+	}
+}
+
+func collect(q *RemoteUploadQueue) map[string]RemoteUploadResult {
+	results := map[string]RemoteUploadResult{}
+	for r := range q.Results() {
+		results[r.Item.URL] = r
+	}
+	return results
+}
+
+func TestRemoteUploadQueue(t *testing.T) {
+	s := newQueueServer(t)
+	q := s.client().NewRemoteUploadQueue(context.Background(), queueOptions(3, 0))
+
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, q.Add(RemoteUploadItem{URL: fmt.Sprintf("http://example.com/%d.txt", i)}))
+	}
+	assert.Nil(t, q.Add(RemoteUploadItem{URL: "http://example.com/fox.txt", FolderID: "4", Headers: map[string]string{"Cookie": "a=b"}}))
+	q.Close()
+	results := collect(q)
+
+	assert.Len(t, results, 21)
+	for url, r := range results {
+		assert.Nil(t, r.Err, url)
+		assert.EqualValues(t, 1, r.Attempts)
+		assert.EqualValues(t, RemoteUploadFinished, r.File.Status)
+		assert.EqualValues(t, url, r.File.Remoteurl)
+	}
+	assert.True(t, s.maxActive <= 3, "max active %d", s.maxActive)
+	assert.EqualValues(t, map[string]string{"folder": "4", "headers": "Cookie: a=b"}, s.params["http://example.com/fox.txt"])
+	assert.Equal(t, ErrQueueClosed, q.Add(RemoteUploadItem{URL: "http://example.com/late.txt"}))
+}
+
+func TestRemoteUploadQueueRetries(t *testing.T) {
+	s := newQueueServer(t)
+	s.failures = 2
+	q := s.client().NewRemoteUploadQueue(context.Background(), queueOptions(2, 2))
+
+	q.Add(RemoteUploadItem{URL: "http://example.com/fail.txt"}, RemoteUploadItem{URL: "http://example.com/ok.txt"})
+	q.Close()
+	results := collect(q)
+
+	assert.Nil(t, results["http://example.com/fail.txt"].Err)
+	assert.EqualValues(t, 3, results["http://example.com/fail.txt"].Attempts)
+	assert.EqualValues(t, 1, results["http://example.com/ok.txt"].Attempts)
+}
+
+func TestRemoteUploadQueueRetriesExhausted(t *testing.T) {
+	s := newQueueServer(t)
+	s.failures = 2
+	q := s.client().NewRemoteUploadQueue(context.Background(), queueOptions(1, 1))
+
+	q.Add(RemoteUploadItem{URL: "http://example.com/fail.txt"})
+	q.Close()
+	r := collect(q)["http://example.com/fail.txt"]
+
+	assert.Nil(t, r.File)
+	assert.EqualValues(t, 2, r.Attempts)
+	assert.True(t, errors.Is(r.Err, ErrRemoteUploadFailed))
+}
+
+func TestRemoteUploadQueuePollErrors(t *testing.T) {
+	s := newQueueServer(t)
+	s.flaky = 3
+	q := s.client().NewRemoteUploadQueue(context.Background(), queueOptions(1, 2))
+
+	q.Add(RemoteUploadItem{URL: "http://example.com/fox.txt"})
+	q.Close()
+	r := collect(q)["http://example.com/fox.txt"]
+
+	assert.Nil(t, r.Err)
+	assert.EqualValues(t, 1, r.Attempts)
+	assert.EqualValues(t, 1, s.attempts["http://example.com/fox.txt"])
+}
+
+func TestRemoteUploadQueueCanceled(t *testing.T) {
+	s := newQueueServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	q := s.client().NewRemoteUploadQueue(ctx, queueOptions(1, 0))
+
+	for i := 0; i < 5; i++ {
+		q.Add(RemoteUploadItem{URL: fmt.Sprintf("http://example.com/%d.txt", i)})
+	}
+	cancel()
+	assert.True(t, errors.Is(q.Add(RemoteUploadItem{URL: "http://example.com/late.txt"}), context.Canceled))
+	results := collect(q)
+
+	assert.Len(t, results, 5)
+	for _, r := range results {
+		if r.Err != nil {
+			assert.True(t, errors.Is(r.Err, context.Canceled))
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointGroup groups api endpoints sharing a rate limit.
+type EndpointGroup int
+
+const (
+	// GroupInfo covers account, file info, listing and management endpoints.
+	GroupInfo EndpointGroup = iota
+	// GroupUpload covers upload link requests.
+	GroupUpload
+	// GroupRemoteUpload covers remote upload add and status endpoints.
+	GroupRemoteUpload
+)
+
+// String returns the group name.
+func (g EndpointGroup) String() string {
+	switch g {
+	case GroupInfo:
+		return "info"
+	case GroupUpload:
+		return "upload"
+	case GroupRemoteUpload:
+		return "remoteupload"
+	}
+	return "unknown"
+}
+
+func endpointGroup(p string) EndpointGroup {
+	switch {
+	case p == "/file/ul":
+		return GroupUpload
+	case strings.HasPrefix(p, "/remotedl/"):
+		return GroupRemoteUpload
+	}
+	return GroupInfo
+}
+
+// WithRateLimit limits requests of group to rate requests per second
+// allowing bursts of up to burst requests, a Client is safe to share
+// between goroutines and all of them wait on the same limiter.
+// When the api reports a rate limit (509 or 429) the rate is halved
+// and then slowly restored on successful requests.
+func WithRateLimit(group EndpointGroup, rate float64, burst int) Option {
+	return func(c *Client) {
+		if c.limiters == nil {
+			c.limiters = make(map[EndpointGroup]*limiter)
+		}
+		c.limiters[group] = newLimiter(rate, burst)
+	}
+}
+
+// limiter is a token bucket with an adaptive rate.
+type limiter struct {
+	mu     sync.Mutex
+	base   float64
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		base:   rate,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token if available or returns how long to wait for one.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.base <= 0 {
+		return nil
+	}
+	for {
+		d := l.reserve(time.Now())
+		if d == 0 {
+			return nil
+		}
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// observe adapts the rate to the outcome of a request.
+func (l *limiter) observe(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case isRateLimited(err):
+		l.rate /= 2
+		if min := l.base / 16; l.rate < min {
+			l.rate = min
+		}
+		l.tokens = 0
+	case err == nil && l.rate < l.base:
+		l.rate += l.base / 16
+		if l.rate > l.base {
+			l.rate = l.base
+		}
+	}
+}
+
+func isRateLimited(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == statusBandwidthExceeded || apiErr.Status == http.StatusTooManyRequests
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode == statusBandwidthExceeded || respErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
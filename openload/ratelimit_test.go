@@ -0,0 +1,73 @@
+package openload
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestEndpointGroup(t *testing.T) {
+	assert.Equal(t, GroupInfo, endpointGroup("/file/info"))
+	assert.Equal(t, GroupInfo, endpointGroup("/file/listfolder"))
+	assert.Equal(t, GroupUpload, endpointGroup("/file/ul"))
+	assert.Equal(t, GroupRemoteUpload, endpointGroup("/remotedl/add"))
+	assert.Equal(t, GroupRemoteUpload, endpointGroup("/remotedl/status"))
+}
+
+func TestLimiterReserve(t *testing.T) {
+	l := newLimiter(10, 2)
+	now := l.last
+
+	assert.EqualValues(t, 0, l.reserve(now))
+	assert.EqualValues(t, 0, l.reserve(now))
+	assert.EqualValues(t, 100*time.Millisecond, l.reserve(now))
+	assert.EqualValues(t, 0, l.reserve(now.Add(100*time.Millisecond)))
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	l := newLimiter(0.001, 1)
+	assert.Nil(t, l.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.wait(ctx))
+}
+
+func TestLimiterAdapts(t *testing.T) {
+	l := newLimiter(16, 1)
+
+	l.observe(&APIError{Status: 509})
+	assert.EqualValues(t, 8, l.rate)
+	for i := 0; i < 10; i++ {
+		l.observe(&APIError{Status: 509})
+	}
+	assert.EqualValues(t, 1, l.rate)
+	l.observe(&APIError{Status: 404})
+	assert.EqualValues(t, 1, l.rate)
+	for i := 0; i < 20; i++ {
+		l.observe(nil)
+	}
+	assert.EqualValues(t, 16, l.rate)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/listfolder").
+		Times(3).
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"folders":[],"files":[]}}`)
+
+	client := New("LOGIN", "KEY", nil, WithRateLimit(GroupInfo, 50, 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.ListFolder("5")
+		assert.Nil(t, err)
+	}
+
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
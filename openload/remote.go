@@ -0,0 +1,183 @@
+package openload
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Default polling intervals of RemoteUploadWatcher.
+const (
+	defaultRemotePollMin = time.Second
+	defaultRemotePollMax = 30 * time.Second
+)
+
+// maxRemoteMissingPolls is how many polls in a row may not list
+// a freshly added remote upload before it is reported as not found.
+const maxRemoteMissingPolls = 3
+
+// RemoteUploadWaitOptions configures WaitRemoteUpload and WatchRemoteUpload.
+type RemoteUploadWaitOptions struct {
+	// Progress receives the progress computed from BytesLoaded and BytesTotal
+	// after every poll, Rate is measured between the last two polls.
+	Progress ProgressFunc
+	// MinInterval is the delay between the first polls (Default: 1s),
+	// it doubles while the upload makes no progress.
+	MinInterval time.Duration
+	// MaxInterval caps the delay between polls (Default: 30s).
+	MaxInterval time.Duration
+}
+
+// withDefaults fills the unset polling intervals.
+func (opts RemoteUploadWaitOptions) withDefaults() RemoteUploadWaitOptions {
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = defaultRemotePollMin
+	}
+	if opts.MaxInterval < opts.MinInterval {
+		opts.MaxInterval = defaultRemotePollMax
+		if opts.MaxInterval < opts.MinInterval {
+			opts.MaxInterval = opts.MinInterval
+		}
+	}
+	return opts
+}
+
+// RemoteUploadWatcher polls the status of a remote upload until it
+// finishes or fails, see WatchRemoteUpload.
+type RemoteUploadWatcher struct {
+	client   *Client
+	ctx      context.Context
+	id       string
+	opts     RemoteUploadWaitOptions
+	backoff  RetryPolicy
+	polls    int
+	missing  int
+	lastPoll time.Time
+	rate     float64
+	status   *RemoteUploadStatusResponse
+	err      error
+	done     bool
+}
+
+// WatchRemoteUpload returns a watcher polling the status of the remote upload id,
+// updates are read with Next and Status:
+//
+//	w := client.WatchRemoteUpload(ctx, remote.ID, openload.RemoteUploadWaitOptions{})
+//	for w.Next() {
+//		fmt.Println(w.Status().Status, w.Progress().BytesSent)
+//	}
+//	if err := w.Err(); err != nil {
+//		...
+//	}
+//
+// https://openload.co/api#remoteul-check
+func (c *Client) WatchRemoteUpload(ctx context.Context, id string, opts RemoteUploadWaitOptions) *RemoteUploadWatcher {
+	opts = opts.withDefaults()
+	return &RemoteUploadWatcher{
+		client:  c,
+		ctx:     ctx,
+		id:      id,
+		opts:    opts,
+		backoff: RetryPolicy{MinBackoff: opts.MinInterval, MaxBackoff: opts.MaxInterval},
+	}
+}
+
+// Next waits for the next poll and reports whether a new status is available,
+// it returns false once the upload finished, failed or polling failed.
+// A remote upload not listed yet is polled again a few times before
+// failing with ErrNotFound.
+func (w *RemoteUploadWatcher) Next() bool {
+	if w.done {
+		return false
+	}
+	for {
+		if w.status != nil || w.missing > 0 {
+			if err := sleep(w.ctx, w.backoff.backoff(w.polls)); err != nil {
+				return w.fail(err)
+			}
+		}
+		statuses, err := w.client.RemoteUploadStatusContext(w.ctx, -1, w.id)
+		if err != nil {
+			return w.fail(err)
+		}
+		status, ok := statuses[w.id]
+		if ok {
+			w.update(status, time.Now())
+			return true
+		}
+		w.missing++
+		w.polls++
+		if w.status != nil || w.missing >= maxRemoteMissingPolls {
+			return w.fail(fmt.Errorf("openload: remote upload %s: %w", w.id, ErrNotFound))
+		}
+	}
+}
+
+// update records a polled status, the rate is measured between
+// the last two polls as the upload may have started long before.
+func (w *RemoteUploadWatcher) update(status RemoteUploadStatusResponse, now time.Time) {
+	if w.status == nil || status.BytesLoaded != w.status.BytesLoaded {
+		w.polls = 1
+	} else {
+		w.polls++
+	}
+	if w.status != nil {
+		w.rate = 0
+		if elapsed := now.Sub(w.lastPoll).Seconds(); elapsed > 0 && status.BytesLoaded > w.status.BytesLoaded {
+			w.rate = float64(status.BytesLoaded-w.status.BytesLoaded) / elapsed
+		}
+	}
+	w.status = &status
+	w.lastPoll = now
+	if w.opts.Progress != nil {
+		w.opts.Progress(w.Progress())
+	}
+
+	if status.Status.IsFailed() {
+		w.err = &RemoteUploadFailedError{ID: w.id, Status: status}
+	}
+	w.done = status.Status.IsTerminal()
+}
+
+func (w *RemoteUploadWatcher) fail(err error) bool {
+	w.err = err
+	w.done = true
+	return false
+}
+
+// Status returns the last polled status, nil before the first call to Next.
+func (w *RemoteUploadWatcher) Status() *RemoteUploadStatusResponse {
+	return w.status
+}
+
+// Progress returns the progress of the last polled status.
+func (w *RemoteUploadWatcher) Progress() Progress {
+	if w.status == nil {
+		return Progress{}
+	}
+	progress := Progress{BytesSent: int64(w.status.BytesLoaded), Total: int64(w.status.BytesTotal), Rate: w.rate}
+	if progress.Total > progress.BytesSent && progress.Rate > 0 {
+		progress.ETA = time.Duration(float64(progress.Total-progress.BytesSent) / progress.Rate * float64(time.Second))
+	}
+	return progress
+}
+
+// Err returns the error that stopped the watcher, a *RemoteUploadFailedError
+// if the remote upload failed.
+func (w *RemoteUploadWatcher) Err() error {
+	return w.err
+}
+
+// WaitRemoteUpload polls the status of the remote upload id until it finishes
+// and returns its final status holding the file id (Extid) and URL.
+// A *RemoteUploadFailedError is returned if the remote upload fails.
+// https://openload.co/api#remoteul-check
+func (c *Client) WaitRemoteUpload(ctx context.Context, id string, opts RemoteUploadWaitOptions) (*RemoteUploadStatusResponse, error) {
+	w := c.WatchRemoteUpload(ctx, id, opts)
+	for w.Next() {
+	}
+	if err := w.Err(); err != nil {
+		return nil, err
+	}
+	return w.Status(), nil
+}
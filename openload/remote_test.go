@@ -0,0 +1,150 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// remoteServer serves /1/remotedl/status returning the next of states
+// on every poll, the last state is repeated.
+// The first hidden polls do not list the remote upload.
+type remoteServer struct {
+	apiServer
+	mu     sync.Mutex
+	states []string
+	polls  int
+	hidden int
+	empty  int
+}
+
+func newRemoteServer(t *testing.T, states ...string) *remoteServer {
+	s := &remoteServer{states: states}
+	s.apiServer = newAPIServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		s.mu.Lock()
+		if s.hidden > 0 {
+			s.hidden--
+			s.empty++
+			s.mu.Unlock()
+			fmt.Fprint(w, `{"status":200,"msg":"OK","result":{}}`)
+			return
+		}
+		state := s.states[s.polls]
+		if s.polls < len(s.states)-1 {
+			s.polls++
+		}
+		s.mu.Unlock()
+		if id != "12" {
+			s.mu.Lock()
+			s.empty++
+			s.mu.Unlock()
+			fmt.Fprint(w, `{"status":200,"msg":"OK","result":{}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"12":{"id":12,"remoteurl":"http://example.com/fox.txt","status":%s,"folderid":"4","extid":"72fA-_Lq8Ak3","url":"https://openload.co/f/72fA-_Lq8Ak3"}}}`, state)
+	}))
+	return s
+}
+
+var fastPolls = RemoteUploadWaitOptions{MinInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+
+func TestWaitRemoteUpload(t *testing.T) {
+	s := newRemoteServer(t,
+		`"new","bytes_loaded":false,"bytes_total":false`,
+		`"downloading","bytes_loaded":"50","bytes_total":"200"`,
+		`"downloading","bytes_loaded":"150","bytes_total":"200"`,
+		`"finished","bytes_loaded":"200","bytes_total":"200"`,
+	)
+	opts := fastPolls
+	var progress []Progress
+	opts.Progress = func(p Progress) { progress = append(progress, p) }
+
+	status, err := s.client().WaitRemoteUpload(context.Background(), "12", opts)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, RemoteUploadFinished, status.Status)
+	assert.EqualValues(t, "72fA-_Lq8Ak3", status.Extid)
+	assert.EqualValues(t, "https://openload.co/f/72fA-_Lq8Ak3", status.URL)
+	assert.Len(t, progress, 4)
+	assert.EqualValues(t, 50, progress[1].BytesSent)
+	assert.EqualValues(t, 200, progress[1].Total)
+	assert.EqualValues(t, 200, progress[3].BytesSent)
+}
+
+func TestWaitRemoteUploadFailed(t *testing.T) {
+	s := newRemoteServer(t, `"downloading"`, `"error"`)
+
+	status, err := s.client().WaitRemoteUpload(context.Background(), "12", fastPolls)
+
+	assert.Nil(t, status)
+	assert.True(t, errors.Is(err, ErrRemoteUploadFailed))
+	var failed *RemoteUploadFailedError
+	assert.True(t, errors.As(err, &failed))
+	assert.EqualValues(t, "12", failed.ID)
+	assert.EqualValues(t, "http://example.com/fox.txt", failed.Status.Remoteurl)
+}
+
+func TestWaitRemoteUploadNotFound(t *testing.T) {
+	s := newRemoteServer(t, `"new"`)
+
+	_, err := s.client().WaitRemoteUpload(context.Background(), "13", fastPolls)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.EqualValues(t, maxRemoteMissingPolls, s.empty)
+}
+
+func TestWaitRemoteUploadNotListedYet(t *testing.T) {
+	s := newRemoteServer(t, `"finished"`)
+	s.hidden = maxRemoteMissingPolls - 1
+
+	status, err := s.client().WaitRemoteUpload(context.Background(), "12", fastPolls)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, RemoteUploadFinished, status.Status)
+}
+
+func TestRemoteUploadWatcherRate(t *testing.T) {
+	w := &RemoteUploadWatcher{}
+	now := time.Now()
+
+	w.update(RemoteUploadStatusResponse{Status: RemoteUploadDownloading, BytesLoaded: 500 << 20, BytesTotal: 600 << 20}, now)
+	assert.EqualValues(t, 0, w.Progress().Rate)
+	assert.EqualValues(t, 0, w.Progress().ETA)
+
+	w.update(RemoteUploadStatusResponse{Status: RemoteUploadDownloading, BytesLoaded: 510 << 20, BytesTotal: 600 << 20}, now.Add(2*time.Second))
+	progress := w.Progress()
+	assert.EqualValues(t, 510<<20, progress.BytesSent)
+	assert.EqualValues(t, 5<<20, progress.Rate)
+	assert.EqualValues(t, 18*time.Second, progress.ETA)
+}
+
+func TestWaitRemoteUploadCanceled(t *testing.T) {
+	s := newRemoteServer(t, `"downloading"`)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := s.client().WaitRemoteUpload(ctx, "12", fastPolls)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRemoteUploadWatcher(t *testing.T) {
+	s := newRemoteServer(t, `"new"`, `"downloading"`, `"finished"`)
+
+	w := s.client().WatchRemoteUpload(context.Background(), "12", fastPolls)
+	var states []RemoteUploadState
+	for w.Next() {
+		states = append(states, w.Status().Status)
+	}
+
+	assert.Nil(t, w.Err())
+	assert.Equal(t, []RemoteUploadState{RemoteUploadNew, RemoteUploadDownloading, RemoteUploadFinished}, states)
+	assert.False(t, w.Next())
+}
@@ -0,0 +1,123 @@
+package openload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// snippetSize is the maximum number of body bytes kept in ResponseError.
+const snippetSize = 256
+
+// maxResponseSize caps how much of a response body is read.
+const maxResponseSize = 32 << 20
+
+// ResponseError represents a response which is not a valid api envelope
+// e.g. an html error page returned by a proxy or a truncated body.
+type ResponseError struct {
+	// StatusCode is the http status code of the response.
+	StatusCode int
+	// ContentType is the Content-Type header of the response.
+	ContentType string
+	// Body is the beginning of the response body.
+	Body string
+	// Err is the underlying decoding error.
+	Err error
+	// RetryAfter is the Retry-After header of the response if any.
+	RetryAfter time.Duration
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("openload: unexpected response (http %d, %q): %v: %q", e.StatusCode, e.ContentType, e.Err, e.Body)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// envelope represents the common response of all api endpoints.
+type envelope struct {
+	Status *int            `json:"status"`
+	Msg    *string         `json:"msg"`
+	Result json.RawMessage `json:"result"`
+}
+
+var (
+	errMissingStatus = errors.New("missing status field")
+	errContentType   = errors.New("unexpected content type")
+)
+
+// decodeEnvelope decodes the api envelope in data and unmarshals
+// its result into result, a missing or null result leaves result untouched.
+func decodeEnvelope(data []byte, result interface{}) error {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	if env.Status == nil {
+		return errMissingStatus
+	}
+	var msg string
+	if env.Msg != nil {
+		msg = *env.Msg
+	}
+	if err := checkStatus(*env.Status, msg); err != nil {
+		return err
+	}
+	if len(env.Result) == 0 || bytes.Equal(env.Result, []byte("null")) {
+		return nil
+	}
+	return json.Unmarshal(env.Result, result)
+}
+
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/json", "text/json", "text/javascript", "application/javascript", "text/plain":
+		return true
+	}
+	return false
+}
+
+func processResponse(response *http.Response, result interface{}) error {
+	contentType := response.Header.Get("Content-Type")
+	data, err := ioutil.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if !isJSONContentType(contentType) {
+		err = errContentType
+	} else {
+		err = decodeEnvelope(data, result)
+	}
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.RetryAfter = parseRetryAfter(response.Header.Get("Retry-After"))
+		return err
+	}
+	if len(data) > snippetSize {
+		data = data[:snippetSize]
+	}
+	return &ResponseError{
+		StatusCode:  response.StatusCode,
+		ContentType: contentType,
+		Body:        string(data),
+		Err:         err,
+		RetryAfter:  parseRetryAfter(response.Header.Get("Retry-After")),
+	}
+}
@@ -0,0 +1,100 @@
+package openload
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func response(status int, contentType, body string) *http.Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestProcessResponse(t *testing.T) {
+	var link UploadURLResponse
+	err := processResponse(response(200, "application/json; charset=utf-8", `{"status":200,"msg":"OK","result":{"url":"https://example.com/ul"}}`), &link)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "https://example.com/ul", link.URL)
+}
+
+func TestProcessResponseMalformed(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"html", 502, "text/html", "<html><body>Bad Gateway</body></html>"},
+		{"truncated", 200, "application/json", `{"status":200,"msg":"OK","result":{"url":`},
+		{"empty", 200, "", ""},
+		{"missing status", 200, "", `{"msg":"OK","result":true}`},
+		{"not an object", 200, "", `[1,2,3]`},
+		{"long body", 500, "text/html", strings.Repeat("x", 4096)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result interface{}
+			err := processResponse(response(tt.status, tt.contentType, tt.body), &result)
+
+			var respErr *ResponseError
+			assert.True(t, errors.As(err, &respErr))
+			assert.EqualValues(t, tt.status, respErr.StatusCode)
+			assert.EqualValues(t, tt.contentType, respErr.ContentType)
+			assert.True(t, len(respErr.Body) <= snippetSize)
+			assert.Contains(t, err.Error(), strconv.Itoa(tt.status))
+		})
+	}
+}
+
+func TestProcessResponseNullResult(t *testing.T) {
+	tests := []string{
+		`{"status":200,"msg":"OK","result":null}`,
+		`{"status":200,"msg":"OK"}`,
+		`{"status":200}`,
+	}
+	for _, body := range tests {
+		var renamed RenameFileResponse
+		err := processResponse(response(200, "", body), &renamed)
+
+		assert.Nil(t, err, body)
+		assert.EqualValues(t, false, renamed, body)
+	}
+}
+
+func TestProcessResponseMissingMsg(t *testing.T) {
+	var result interface{}
+	err := processResponse(response(200, "", `{"status":404}`), &result)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func FuzzDecodeEnvelope(f *testing.F) {
+	f.Add([]byte(`{"status":200,"msg":"OK","result":{"url":"https://example.com","valid_until":"2015-01-09 00:02:50"}}`))
+	f.Add([]byte(`{"status":200,"msg":"OK","result":null}`))
+	f.Add([]byte(`{"status":403,"msg":null}`))
+	f.Add([]byte(`{"status":"200"}`))
+	f.Add([]byte(`<html></html>`))
+	f.Add([]byte(`null`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var link UploadURLResponse
+		decodeEnvelope(data, &link)
+		var files FilesInfoResponse
+		decodeEnvelope(data, &files)
+		var v interface{}
+		decodeEnvelope(data, &v)
+	})
+}
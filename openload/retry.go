@@ -0,0 +1,162 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how idempotent api calls are retried
+// (AccountInfo, FilesInfo, UploadLink, RemoteUploadStatus, ListFolder, ...).
+// Non idempotent calls such as RemoteUpload, RenameFile or DeleteFile
+// are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	// values lower than 2 disable retrying.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry, it doubles on each retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts, 0 means no cap.
+	MaxBackoff time.Duration
+	// Jitter randomly shortens each wait by up to this fraction (0 to 1).
+	Jitter float64
+	// Retryable reports whether err is worth retrying
+	// nil means DefaultRetryable.
+	Retryable func(err error) bool
+	// OnRetry is called before waiting for the next attempt, optional.
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// DefaultRetryPolicy is a reasonable policy for WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+	Jitter:      0.5,
+}
+
+// WithRetryPolicy enables retrying idempotent calls using p.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// DefaultRetryable reports whether err is a transient failure:
+// network errors, 5xx and 429 http responses and 5xx api statuses
+// including 509 bandwidth exceeded. Context errors are never retryable.
+func DefaultRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status >= http.StatusInternalServerError
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode >= http.StatusInternalServerError || respErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// backoff returns the wait before the given retry (1 for the first retry).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff) && d < math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// do calls fn until it succeeds, fails with a non retryable error,
+// ctx is done or MaxAttempts is reached.
+func (p *RetryPolicy) do(ctx context.Context, fn func() error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return err
+		}
+		wait := p.backoff(attempt)
+		if after := retryAfter(err); after > wait {
+			wait = after
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, wait)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header value
+// either delay seconds or an http date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// getIdempotent is like get but retries according to the client RetryPolicy.
+func (c *Client) getIdempotent(ctx context.Context, p string, q map[string]string, result interface{}) error {
+	if c.retry == nil {
+		return c.get(ctx, p, q, result)
+	}
+	return c.retry.do(ctx, func() error {
+		return c.get(ctx, p, q, result)
+	})
+}
@@ -0,0 +1,148 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func retryClient(retries *int) *Client {
+	return New("LOGIN", "KEY", nil, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			*retries++
+		},
+	}))
+}
+
+func TestRetryTransient(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/listfolder").
+		Reply(502).
+		SetHeader("Content-Type", "text/html").
+		BodyString("<html>Bad Gateway</html>")
+	gock.New(buildAPIURL()).
+		Get("/file/listfolder").
+		Reply(200).
+		BodyString(`{"status":509,"msg":"bandwidth usage exceeded","result":null}`)
+	gock.New(buildAPIURL()).
+		Get("/file/listfolder").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"folders":[{"id":"5144","name":".videothumb"}],"files":[]}}`)
+
+	var retries int
+	listed, err := retryClient(&retries).ListFolder("5")
+
+	assert.Nil(t, err)
+	assert.Len(t, listed.Folders, 1)
+	assert.EqualValues(t, 2, retries)
+	assert.True(t, gock.IsDone())
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/info").
+		Times(3).
+		Reply(200).
+		BodyString(`{"status":500,"msg":"internal error","result":null}`)
+
+	var retries int
+	_, err := retryClient(&retries).FileInfo("72fA-_Lq8Ak6")
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.EqualValues(t, 500, apiErr.Status)
+	assert.EqualValues(t, 2, retries)
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/file/ul").
+		Reply(200).
+		BodyString(`{"status":403,"msg":"wrong login","result":null}`)
+
+	var retries int
+	_, err := retryClient(&retries).UploadLink("", "", false)
+
+	assert.True(t, errors.Is(err, ErrPermissionDenied))
+	assert.EqualValues(t, 0, retries)
+}
+
+func TestRetryNotIdempotent(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/remotedl/add").
+		Reply(200).
+		BodyString(`{"status":500,"msg":"internal error","result":null}`)
+
+	var retries int
+	_, err := retryClient(&retries).RemoteUpload("http://google.com/favicon.ico", "", nil)
+
+	assert.Error(t, err)
+	assert.EqualValues(t, 0, retries)
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	var waits []time.Duration
+	p := RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			waits = append(waits, wait)
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := p.do(ctx, func() error {
+		cancel()
+		return &APIError{Status: 509, RetryAfter: time.Minute}
+	})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []time.Duration{time.Minute}, waits)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.EqualValues(t, 0, parseRetryAfter(""))
+	assert.EqualValues(t, 0, parseRetryAfter("soon"))
+	assert.EqualValues(t, 120*time.Second, parseRetryAfter("120"))
+	d := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, d > 59*time.Minute && d <= time.Hour, d)
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.EqualValues(t, time.Second, p.backoff(1))
+	assert.EqualValues(t, 2*time.Second, p.backoff(2))
+	assert.EqualValues(t, 4*time.Second, p.backoff(3))
+	assert.EqualValues(t, 5*time.Second, p.backoff(4))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.True(t, d > time.Second/2 && d <= time.Second, d)
+	}
+}
+
+func TestBackoffUncapped(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second}
+
+	assert.EqualValues(t, time.Second, p.backoff(1))
+	assert.EqualValues(t, 2*time.Second, p.backoff(2))
+	assert.EqualValues(t, 8*time.Second, p.backoff(4))
+	assert.True(t, p.backoff(1000) > 0)
+}
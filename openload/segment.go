@@ -0,0 +1,169 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// segmentsSuffix names the marker file created next to a part file
+// preallocated by fetchSegments, such a part file can not be resumed.
+const segmentsSuffix = ".segments"
+
+// maxSegmentRetries is how many times fetchSegments resumes a broken
+// segment with the same link.
+const maxSegmentRetries = 3
+
+// segment is a range of the content fetched by fetchSegments,
+// offset is the next byte to fetch and end the last one.
+type segment struct {
+	offset int64
+	end    int64
+}
+
+// preallocate sizes file for a segmented download of size bytes
+// and splits it into n segments.
+func preallocate(file *os.File, size int64, n int, progress *progressWriter) ([]*segment, error) {
+	marker, err := os.Create(file.Name() + segmentsSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if err := marker.Close(); err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		progress.mu.Lock()
+		progress.sent, progress.total = 0, size
+		progress.mu.Unlock()
+	}
+
+	if int64(n) > size {
+		n = int(size)
+	}
+	chunk := (size + int64(n) - 1) / int64(n)
+	var segments []*segment
+	for start := int64(0); start < size; start += chunk {
+		end := start + chunk - 1
+		if end >= size {
+			end = size - 1
+		}
+		segments = append(segments, &segment{offset: start, end: end})
+	}
+	return segments, nil
+}
+
+// fetchSegments fetches the remaining part of segments from url into file
+// concurrently, see fetchSegment. A failed segment does not stop the others
+// so that a later call with a new link only fetches what is left.
+// The marker of file is removed once every segment is complete.
+func (c *Client) fetchSegments(ctx context.Context, url string, file *os.File, segments []*segment, progress *progressWriter) error {
+	errs := make(chan error, len(segments))
+	var wg sync.WaitGroup
+	for _, seg := range segments {
+		if seg.offset > seg.end {
+			continue
+		}
+		wg.Add(1)
+		go func(seg *segment) {
+			defer wg.Done()
+			if err := c.fetchSegment(ctx, url, file, seg, progress); err != nil {
+				errs <- err
+			}
+		}(seg)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	return removeMarker(file)
+}
+
+// fetchSegment fetches the remaining part of seg from url, a broken
+// transfer is resumed with the same link up to maxSegmentRetries times.
+// Status errors (e.g. an expired link) are returned at once, they need a new link.
+func (c *Client) fetchSegment(ctx context.Context, url string, file *os.File, seg *segment, progress *progressWriter) error {
+	for retries := 0; ; retries++ {
+		w := &sectionWriter{file: file, offset: seg.offset, progress: progress}
+		err := c.fetchSection(ctx, url, w, seg.end)
+		seg.offset = w.offset
+		if err == nil {
+			return nil
+		}
+		var statusErr *statusError
+		if errors.As(err, &statusErr) || err == errRangeIgnored || ctx.Err() != nil || retries >= maxSegmentRetries {
+			return err
+		}
+	}
+}
+
+// removeMarker removes the segments marker of file if any.
+func removeMarker(file *os.File) error {
+	if err := os.Remove(file.Name() + segmentsSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// fetchSection writes the w.offset-end range of url to w.
+func (c *Client) fetchSection(ctx context.Context, url string, w *sectionWriter, end int64) error {
+	response, err := c.openRange(ctx, url, w.offset, end)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusPartialContent {
+		return errRangeIgnored
+	}
+	want := end - w.offset + 1
+	n, err := io.Copy(w, io.LimitReader(response.Body, want))
+	if err == nil && n < want {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// supportsRanges probes url with a one byte range request
+// and reports whether it serves ranges of a size bytes content.
+func (c *Client) supportsRanges(ctx context.Context, url string, size int64) (bool, error) {
+	response, err := c.openRange(ctx, url, 0, 0)
+	if err != nil {
+		return false, err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusPartialContent {
+		return false, nil
+	}
+	// Content-Range: bytes 0-0/size
+	contentRange := response.Header.Get("Content-Range")
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return false, nil
+	}
+	total, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	return err == nil && total == size, nil
+}
+
+// sectionWriter writes sequentially to file from offset.
+type sectionWriter struct {
+	file     *os.File
+	offset   int64
+	progress *progressWriter
+}
+
+func (w *sectionWriter) Write(p []byte) (int, error) {
+	n, err := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	if n > 0 && w.progress != nil {
+		w.progress.add(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,141 @@
+package openload
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var segmentContent = strings.Repeat("The quick brown fox jumps over the lazy dog\n", 100)
+
+func TestDownloadToFileSegments(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	s.sha1 = fmt.Sprintf("%x", sha1.Sum([]byte(segmentContent)))
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	var last Progress
+	ordered := true
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{
+		Segments: 4,
+		Progress: func(p Progress) {
+			ordered = ordered && p.BytesSent > last.BytesSent
+			last = p
+		},
+	})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+	sort.Strings(s.ranges)
+	assert.Equal(t, []string{"bytes=0-0", "bytes=0-1099", "bytes=1100-2199", "bytes=2200-3299", "bytes=3300-4399"}, s.ranges)
+	assert.True(t, ordered)
+	assert.EqualValues(t, len(segmentContent), last.BytesSent)
+	assert.EqualValues(t, len(segmentContent), last.Total)
+	_, err = os.Stat(name + ".part.segments")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadToFileSegmentRetry(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	s.breakAfter = 100
+	s.breakRange = "bytes=1100-2199"
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{Segments: 4})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+	assert.Contains(t, s.ranges, "bytes=1200-2199")
+	assert.EqualValues(t, 1, count(s.ranges, "bytes=0-1099"))
+	assert.EqualValues(t, 1, s.tickets)
+}
+
+func TestDownloadToFileSegmentExpired(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	s.expireRange = "bytes=1100-2199"
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{Segments: 4, MaxResumes: 1})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+	assert.EqualValues(t, 2, count(s.ranges, "bytes=1100-2199"))
+	assert.EqualValues(t, 1, count(s.ranges, "bytes=0-1099"))
+	assert.EqualValues(t, 2, s.tickets)
+}
+
+func TestDownloadToFileSegmentsExhausted(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	s.expireRange = "bytes=1100-2199"
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{Segments: 4})
+
+	assert.Error(t, err)
+	_, err = os.Stat(name + ".part.segments")
+	assert.Nil(t, err)
+
+	// The preallocated part file is not resumable, it is fetched again.
+	s.sha1 = fmt.Sprintf("%x", sha1.Sum([]byte(segmentContent)))
+	s.ranges = nil
+	_, err = s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+	assert.Equal(t, []string{""}, s.ranges)
+	_, err = os.Stat(name + ".part.segments")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadToFilePreallocatedPart(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	name := filepath.Join(t.TempDir(), "fox.txt")
+	assert.Nil(t, ioutil.WriteFile(name+".part", make([]byte, len(segmentContent)), 0644))
+	assert.Nil(t, ioutil.WriteFile(name+".part.segments", nil, 0644))
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+}
+
+func count(values []string, value string) int {
+	n := 0
+	for _, v := range values {
+		if v == value {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDownloadToFileSegmentsNoRanges(t *testing.T) {
+	s := newDownloadServer(t, segmentContent)
+	s.noRanges = true
+	name := filepath.Join(t.TempDir(), "fox.txt")
+
+	_, err := s.client().DownloadToFile(context.Background(), "72fA-_Lq8Ak", name, DownloadOptions{Segments: 4})
+
+	assert.Nil(t, err)
+	data, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	assert.EqualValues(t, segmentContent, string(data))
+	assert.Equal(t, []string{"bytes=0-0", ""}, s.ranges)
+}
@@ -0,0 +1,114 @@
+package openload
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// FileStatus is the http like status of a file in FileInfoResponse.
+type FileStatus int
+
+// Known file statuses, other values are preserved as is.
+const (
+	FileOK                         FileStatus = http.StatusOK
+	FileNotFound                   FileStatus = http.StatusNotFound
+	FileUnavailableForLegalReasons FileStatus = http.StatusUnavailableForLegalReasons
+	FileError                      FileStatus = http.StatusInternalServerError
+)
+
+// String returns a readable name of the status.
+func (s FileStatus) String() string {
+	switch s {
+	case FileOK:
+		return "ok"
+	case FileNotFound:
+		return "not found"
+	case FileUnavailableForLegalReasons:
+		return "unavailable for legal reasons"
+	case FileError:
+		return "error"
+	}
+	return "status " + strconv.Itoa(int(s))
+}
+
+// IsAvailable reports whether the file can be downloaded.
+func (s FileStatus) IsAvailable() bool {
+	return s == FileOK
+}
+
+// IsFailed reports whether the file is missing, blocked or broken.
+func (s FileStatus) IsFailed() bool {
+	return s != FileOK
+}
+
+// RemoteUploadState is the status of a remote upload.
+type RemoteUploadState string
+
+// Known remote upload states, other values are preserved as is.
+const (
+	RemoteUploadNew         RemoteUploadState = "new"
+	RemoteUploadDownloading RemoteUploadState = "downloading"
+	RemoteUploadFinished    RemoteUploadState = "finished"
+	RemoteUploadError       RemoteUploadState = "error"
+)
+
+// String returns the state as reported by the api.
+func (s RemoteUploadState) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the known states.
+func (s RemoteUploadState) IsKnown() bool {
+	switch s {
+	case RemoteUploadNew, RemoteUploadDownloading, RemoteUploadFinished, RemoteUploadError:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the remote upload will not change anymore.
+func (s RemoteUploadState) IsTerminal() bool {
+	return s == RemoteUploadFinished || s == RemoteUploadError
+}
+
+// IsFailed reports whether the remote upload failed.
+func (s RemoteUploadState) IsFailed() bool {
+	return s == RemoteUploadError
+}
+
+// ConversionState is the status of a file conversion
+// as reported by RunningConversions and ListFolder (Cstatus).
+type ConversionState string
+
+// Known conversion states, other values are preserved as is.
+const (
+	ConversionPending    ConversionState = "pending"
+	ConversionConverting ConversionState = "converting"
+	ConversionFinished   ConversionState = "finished"
+	ConversionOK         ConversionState = "ok"
+	ConversionError      ConversionState = "error"
+)
+
+// String returns the state as reported by the api.
+func (s ConversionState) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the known states.
+func (s ConversionState) IsKnown() bool {
+	switch s {
+	case ConversionPending, ConversionConverting, ConversionFinished, ConversionOK, ConversionError:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the conversion will not change anymore.
+func (s ConversionState) IsTerminal() bool {
+	return s == ConversionFinished || s == ConversionOK || s == ConversionError
+}
+
+// IsFailed reports whether the conversion failed.
+func (s ConversionState) IsFailed() bool {
+	return s == ConversionError
+}
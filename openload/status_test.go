@@ -0,0 +1,74 @@
+package openload
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStatus(t *testing.T) {
+	assert.EqualValues(t, "ok", FileOK.String())
+	assert.EqualValues(t, "not found", FileNotFound.String())
+	assert.EqualValues(t, "unavailable for legal reasons", FileUnavailableForLegalReasons.String())
+	assert.EqualValues(t, "status 418", FileStatus(418).String())
+	assert.True(t, FileOK.IsAvailable())
+	assert.False(t, FileOK.IsFailed())
+	assert.True(t, FileNotFound.IsFailed())
+	assert.True(t, FileStatus(418).IsFailed())
+}
+
+func TestRemoteUploadState(t *testing.T) {
+	tests := []struct {
+		state    RemoteUploadState
+		known    bool
+		terminal bool
+		failed   bool
+	}{
+		{RemoteUploadNew, true, false, false},
+		{RemoteUploadDownloading, true, false, false},
+		{RemoteUploadFinished, true, true, false},
+		{RemoteUploadError, true, true, true},
+		{"queued", false, false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.known, tt.state.IsKnown(), tt.state.String())
+		assert.Equal(t, tt.terminal, tt.state.IsTerminal(), tt.state.String())
+		assert.Equal(t, tt.failed, tt.state.IsFailed(), tt.state.String())
+	}
+}
+
+func TestConversionState(t *testing.T) {
+	tests := []struct {
+		state    ConversionState
+		known    bool
+		terminal bool
+		failed   bool
+	}{
+		{ConversionPending, true, false, false},
+		{ConversionConverting, true, false, false},
+		{ConversionFinished, true, true, false},
+		{ConversionOK, true, true, false},
+		{ConversionError, true, true, true},
+		{"retrying", false, false, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.known, tt.state.IsKnown(), tt.state.String())
+		assert.Equal(t, tt.terminal, tt.state.IsTerminal(), tt.state.String())
+		assert.Equal(t, tt.failed, tt.state.IsFailed(), tt.state.String())
+	}
+}
+
+func TestStatusUnknownValuePreserved(t *testing.T) {
+	var status RemoteUploadStatusResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"id":3,"status":"queued"}`), &status))
+	assert.Equal(t, RemoteUploadState("queued"), status.Status)
+
+	data, err := json.Marshal(status)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"status":"queued"`)
+
+	var info FileInfoResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"id":"72fA-_Lq8Ak6","status":451}`), &info))
+	assert.Equal(t, FileUnavailableForLegalReasons, info.Status)
+}
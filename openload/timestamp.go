@@ -0,0 +1,142 @@
+package openload
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// timeLayout is the layout of api timestamps.
+const timeLayout = "2006-01-02 15:04:05"
+
+// Timestamp is a time decoded from an api timestamp, either
+// "2006-01-02 15:04:05" in the service time zone or unix seconds.
+// null, false and empty strings decode to the zero time.
+//
+// The layout carries no time zone: it is decoded in UTC and a Client
+// moves it to its location (see WithLocation) when it returns a response.
+// A Timestamp decoded with json.Unmarshal stays in UTC, use InZone
+// to apply the service time zone before comparing it to other times.
+type Timestamp struct {
+	time.Time
+	unix bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Layout timestamps are decoded in UTC, see Timestamp.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*t = Timestamp{}
+		return err
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*t = Timestamp{Time: time.Unix(seconds, 0).UTC(), unix: true}
+		return nil
+	}
+	parsed, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp{Time: parsed}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the format it was decoded from.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.IsZero():
+		return []byte(`""`), nil
+	case t.unix:
+		return []byte(strconv.Quote(strconv.FormatInt(t.Unix(), 10))), nil
+	}
+	return json.Marshal(t.Format(timeLayout))
+}
+
+// String returns t formatted like the api does.
+func (t Timestamp) String() string {
+	if t.unix {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	return t.Format(timeLayout)
+}
+
+// InZone returns t with the wall clock of a layout timestamp
+// reinterpreted in loc, unix timestamps are returned unchanged.
+func (t Timestamp) InZone(loc *time.Location) Timestamp {
+	if t.unix || t.IsZero() {
+		return t
+	}
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	t.Time = time.Date(y, mo, d, h, mi, s, t.Nanosecond(), loc)
+	return t
+}
+
+// WithLocation sets the time zone api timestamps are expressed in
+// default is UTC, nil is ignored.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		if loc != nil {
+			c.location = loc
+		}
+	}
+}
+
+var timestampType = reflect.TypeOf(Timestamp{})
+
+// localize moves every Timestamp reachable from v to loc,
+// decoded timestamps are already in UTC.
+func localize(v reflect.Value, loc *time.Location) {
+	if loc == time.UTC {
+		return
+	}
+	localizeValue(v, loc)
+}
+
+func localizeValue(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			localizeValue(v.Elem(), loc)
+		}
+	case reflect.Struct:
+		if v.Type() == timestampType {
+			if v.CanAddr() {
+				ts := v.Addr().Interface().(*Timestamp)
+				*ts = ts.InZone(loc)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				localizeValue(v.Field(i), loc)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			localizeValue(v.Index(i), loc)
+		}
+	case reflect.Map:
+		// Map values are not addressable, copy and store them back.
+		for _, k := range v.MapKeys() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+			localizeValue(e, loc)
+			v.SetMapIndex(k, e)
+		}
+	}
+}
+
+// Expired reports whether the ticket is no longer valid,
+// see Timestamp for tickets not returned by a Client.
+func (r *DownloadTicketResponse) Expired() bool {
+	return !r.ValidUntil.IsZero() && time.Now().After(r.ValidUntil.Time)
+}
+
+// Expired reports whether the upload url is no longer valid,
+// see Timestamp for urls not returned by a Client.
+func (r *UploadURLResponse) Expired() bool {
+	return !r.ValidUntil.IsZero() && time.Now().After(r.ValidUntil.Time)
+}
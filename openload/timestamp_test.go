@@ -0,0 +1,91 @@
+package openload
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected time.Time
+	}{
+		{`"2015-01-09 23:59:54"`, time.Date(2015, 1, 9, 23, 59, 54, 0, time.UTC)},
+		{`"1419791256"`, time.Unix(1419791256, 0)},
+		{`1419791256`, time.Unix(1419791256, 0)},
+		{`""`, time.Time{}},
+		{`null`, time.Time{}},
+		{`false`, time.Time{}},
+	}
+	for _, tt := range tests {
+		var ts Timestamp
+		err := json.Unmarshal([]byte(tt.json), &ts)
+
+		assert.Nil(t, err, tt.json)
+		assert.True(t, tt.expected.Equal(ts.Time), tt.json)
+	}
+
+	var ts Timestamp
+	assert.Error(t, json.Unmarshal([]byte(`"yesterday"`), &ts))
+}
+
+func TestTimestampMarshal(t *testing.T) {
+	for _, data := range []string{`"2015-01-09 23:59:54"`, `"1419791256"`, `""`} {
+		var ts Timestamp
+		assert.Nil(t, json.Unmarshal([]byte(data), &ts))
+
+		marshaled, err := json.Marshal(ts)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, data, string(marshaled))
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(buildAPIURL()).
+		Get("/remotedl/status").
+		Reply(200).
+		BodyString(`{"status":200,"msg":"OK","result":{"20":{"id":20,"remoteurl":"http://google.de/favicon.ico","status":"finished","bytes_loaded":"229","bytes_total":"229","folderid":"4248","added":"2015-02-21 09:03:47","last_update":"2015-02-21 09:04:04","extid":"ANAaeBZus-Q","url":"https://openload.co/f/ANAaeBZus-Q"}}}`)
+
+	loc := time.FixedZone("CET", 3600)
+	status, err := New("LOGIN", "KEY", nil, WithLocation(loc)).RemoteUploadStatus(5, "20")
+
+	assert.Nil(t, err)
+	added := status["20"].Added
+	assert.Equal(t, loc, added.Location())
+	assert.True(t, time.Date(2015, 2, 21, 8, 3, 47, 0, time.UTC).Equal(added.Time))
+	assert.EqualValues(t, "2015-02-21 09:03:47", added.String())
+}
+
+func TestTimestampInZone(t *testing.T) {
+	var ts Timestamp
+	assert.Nil(t, json.Unmarshal([]byte(`"2015-02-21 09:03:47"`), &ts))
+	assert.Equal(t, time.UTC, ts.Location())
+
+	loc := time.FixedZone("CET", 3600)
+	zoned := ts.InZone(loc)
+	assert.Equal(t, loc, zoned.Location())
+	assert.True(t, time.Date(2015, 2, 21, 8, 3, 47, 0, time.UTC).Equal(zoned.Time))
+	assert.EqualValues(t, "2015-02-21 09:03:47", zoned.String())
+
+	var unix Timestamp
+	assert.Nil(t, json.Unmarshal([]byte(`"1419791256"`), &unix))
+	assert.True(t, unix.Time.Equal(unix.InZone(loc).Time))
+}
+
+func TestExpired(t *testing.T) {
+	past := Timestamp{Time: time.Now().Add(-time.Minute)}
+	future := Timestamp{Time: time.Now().Add(time.Minute)}
+
+	assert.True(t, (&DownloadTicketResponse{ValidUntil: past}).Expired())
+	assert.False(t, (&DownloadTicketResponse{ValidUntil: future}).Expired())
+	assert.False(t, (&DownloadTicketResponse{}).Expired())
+	assert.True(t, (&UploadURLResponse{ValidUntil: past}).Expired())
+	assert.False(t, (&UploadURLResponse{ValidUntil: future}).Expired())
+}
@@ -0,0 +1,97 @@
+package openload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FlexInt64 is an int64 decoded from a json number or a numeric string.
+// null, false and empty strings decode to 0.
+type FlexInt64 int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexInt64) UnmarshalJSON(data []byte) error {
+	s, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*n = 0
+		return err
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*n = FlexInt64(i)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("openload: cannot decode %s as int64", data)
+	}
+	*n = FlexInt64(f)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n FlexInt64) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(n), 10)), nil
+}
+
+// FlexFloat64 is a float64 decoded from a json number or a numeric string.
+// null, false and empty strings decode to 0.
+type FlexFloat64 float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexFloat64) UnmarshalJSON(data []byte) error {
+	s, ok, err := flexScalar(data)
+	if err != nil || !ok {
+		*n = 0
+		return err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("openload: cannot decode %s as float64", data)
+	}
+	*n = FlexFloat64(f)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n FlexFloat64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(n))
+}
+
+// FlexString is a string decoded from a json string or number,
+// null and false (used by the api for missing values) decode to "".
+type FlexString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	v, _, err := flexScalar(data)
+	*s = FlexString(v)
+	return err
+}
+
+// String returns s as a string.
+func (s FlexString) String() string {
+	return string(s)
+}
+
+// flexScalar returns the text of a json string or number,
+// ok is false for null, false and empty strings.
+func flexScalar(data []byte) (string, bool, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")), bytes.Equal(data, []byte("false")):
+		return "", false, nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false, err
+		}
+		return s, s != "", nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", false, fmt.Errorf("openload: cannot decode %s as a scalar", data)
+	}
+	return n.String(), true, nil
+}
@@ -0,0 +1,101 @@
+package openload
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlexInt64(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected FlexInt64
+	}{
+		{`12345`, 12345},
+		{`"32922117680"`, 32922117680},
+		{`-1`, -1},
+		{`"-1"`, -1},
+		{`1.5e3`, 1500},
+		{`"12.0"`, 12},
+		{`""`, 0},
+		{`null`, 0},
+		{`false`, 0},
+	}
+	for _, tt := range tests {
+		var n FlexInt64
+		err := json.Unmarshal([]byte(tt.json), &n)
+
+		assert.Nil(t, err, tt.json)
+		assert.Equal(t, tt.expected, n, tt.json)
+	}
+}
+
+func TestFlexInt64Invalid(t *testing.T) {
+	for _, data := range []string{`"abc"`, `true`, `{}`, `[]`} {
+		var n FlexInt64
+		assert.Error(t, json.Unmarshal([]byte(data), &n), data)
+	}
+}
+
+func TestFlexFloat64(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected FlexFloat64
+	}{
+		{`0`, 0},
+		{`12.34`, 12.34},
+		{`"12.34"`, 12.34},
+		{`null`, 0},
+		{`false`, 0},
+	}
+	for _, tt := range tests {
+		var n FlexFloat64
+		err := json.Unmarshal([]byte(tt.json), &n)
+
+		assert.Nil(t, err, tt.json)
+		assert.Equal(t, tt.expected, n, tt.json)
+	}
+}
+
+func TestFlexString(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected FlexString
+	}{
+		{`"The quick brown fox.txt"`, "The quick brown fox.txt"},
+		{`""`, ""},
+		{`20`, "20"},
+		{`null`, ""},
+		{`false`, ""},
+	}
+	for _, tt := range tests {
+		var s FlexString
+		err := json.Unmarshal([]byte(tt.json), &s)
+
+		assert.Nil(t, err, tt.json)
+		assert.Equal(t, tt.expected, s, tt.json)
+	}
+}
+
+func TestFlexMarshal(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Size    FlexInt64   `json:"size"`
+		Balance FlexFloat64 `json:"balance"`
+		URL     FlexString  `json:"url"`
+	}{123, 1.5, "https://openload.co/f/ANAaeBZus-Q"})
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"size":123,"balance":1.5,"url":"https://openload.co/f/ANAaeBZus-Q"}`, string(data))
+}
+
+func TestFileInfoMissingFile(t *testing.T) {
+	var info FileInfoResponse
+	err := json.Unmarshal([]byte(`{"id":"72fA-_Lq8Ak5","status":404,"name":false,"size":false,"sha1":false,"content_type":false}`), &info)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", info.Name)
+	assert.EqualValues(t, 0, info.Size)
+	assert.EqualValues(t, "", info.Sha1)
+	assert.EqualValues(t, "", info.ContentType)
+}
@@ -0,0 +1,236 @@
+package openload
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// UploadOptions holds the optional parameters of an upload.
+// https://openload.co/api#upload
+type UploadOptions struct {
+	// FolderID is the destination folder, empty means home folder.
+	FolderID string
+	// Sha1 is the expected sha1 of the content, optional.
+	Sha1 string
+	// HTTPOnly requests an http upload link instead of https.
+	HTTPOnly bool
+	// Size is the content size reported as Progress.Total, optional.
+	// It is filled from the file size by Upload and UploadFile.
+	Size int64
+	// Progress is called as content is sent, optional.
+	Progress ProgressFunc
+	// ComputeSha1 hashes the content and verifies the sha1 returned
+	// by the server after upload, failing with a *ChecksumError.
+	// Seekable content is hashed before requesting the upload link
+	// so that the hash is passed as Sha1, other content is hashed
+	// while being sent.
+	ComputeSha1 bool
+	// SkipIfInFolder lists FolderID before uploading and returns the file
+	// of the same sha1 found there instead of uploading the content again.
+	// It is a client side lookup limited to FolderID, identical content
+	// in other folders is uploaded again.
+	// It requires Sha1 or ComputeSha1 with seekable content.
+	SkipIfInFolder bool
+}
+
+var errSkipNeedsSha1 = errors.New("openload: SkipIfInFolder requires Sha1 or ComputeSha1 with seekable content")
+
+// UploadReader uploads content read from r until EOF as a file called name.
+// r is streamed, its size does not need to be known in advance.
+// https://openload.co/api#upload
+func (c *Client) UploadReader(ctx context.Context, r io.Reader, name string, opts UploadOptions) (*UploadResponse, error) {
+	return c.upload(ctx, name, r, opts)
+}
+
+// UploadFile uploads content of an already opened file
+// the uploaded file is named after the base name of f.
+// f is read from its current offset and is not closed.
+// https://openload.co/api#upload
+func (c *Client) UploadFile(ctx context.Context, f *os.File, opts UploadOptions) (*UploadResponse, error) {
+	if opts.Size == 0 {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("openload: %s is a directory", f.Name())
+		}
+		if offset, err := f.Seek(0, io.SeekCurrent); err == nil {
+			opts.Size = info.Size() - offset
+		}
+	}
+	return c.UploadReader(ctx, f, filepath.Base(f.Name()), opts)
+}
+
+// upload requests an upload link and posts a multipart body
+// whose file part is read from content.
+func (c *Client) upload(ctx context.Context, name string, content io.Reader, opts UploadOptions) (*UploadResponse, error) {
+	var result UploadResponse
+
+	// Hash seekable content upfront, other content while it is sent.
+	var streamHash hash.Hash
+	if opts.ComputeSha1 {
+		if rs, ok := content.(io.ReadSeeker); ok {
+			sum, err := sha1Seeker(rs)
+			if err != nil {
+				return nil, err
+			}
+			opts.Sha1 = sum
+		} else {
+			streamHash = sha1.New()
+			content = io.TeeReader(content, streamHash)
+		}
+	}
+
+	if opts.SkipIfInFolder {
+		if opts.Sha1 == "" {
+			return nil, errSkipNeedsSha1
+		}
+		existing, err := c.findSha1(ctx, opts.FolderID, opts.Sha1)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	// Get valid upload link.
+	ul, err := c.UploadLinkContext(ctx, opts.FolderID, opts.Sha1, opts.HTTPOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream the content, a failure is propagated to the request body
+	// so that the server never receives a silently truncated file.
+	r, w := io.Pipe()
+	m := multipart.NewWriter(w)
+	done := make(chan error, 1)
+
+	go func() {
+		err := func() error {
+			part, err := m.CreateFormFile("files", name)
+			if err != nil {
+				return err
+			}
+			if opts.Progress != nil {
+				part = newProgressWriter(part, opts.Size, opts.Progress)
+			}
+			if _, err = io.Copy(part, content); err != nil {
+				return err
+			}
+			return m.Close()
+		}()
+		// Report before closing the pipe so that a content error which
+		// failed the request is available once Do returns.
+		done <- err
+		w.CloseWithError(err)
+	}()
+
+	// Upload the content and process the response.
+	request, err := c.newRequest(ctx, http.MethodPost, ul.URL, r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	request.Header.Set("Content-Type", m.FormDataContentType())
+	// The transport waits for the body before returning a cancellation,
+	// end it when ctx is done even if content is blocked.
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.CloseWithError(ctx.Err())
+		case <-stop:
+		}
+	}()
+	response, err := c.httpClient.Do(request)
+	close(stop)
+	// The goroutine is not waited for, it may be blocked reading content
+	// after a cancellation, closing r makes it exit once the read returns.
+	r.Close()
+	select {
+	case contentErr := <-done:
+		if contentErr != nil && contentErr != io.ErrClosedPipe {
+			if err == nil {
+				response.Body.Close()
+			}
+			return nil, contentErr
+		}
+	default:
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	err = processResponse(response, &result)
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.Endpoint = request.URL.Path
+	}
+	if err != nil {
+		return nil, err
+	}
+	localize(reflect.ValueOf(&result), c.location)
+	if c.listings != nil {
+		c.listings.clear()
+	}
+
+	if opts.ComputeSha1 {
+		expected := opts.Sha1
+		if streamHash != nil {
+			expected = hex.EncodeToString(streamHash.Sum(nil))
+		}
+		if !strings.EqualFold(expected, result.Sha1) {
+			return &result, &ChecksumError{Expected: expected, Actual: result.Sha1}
+		}
+	}
+	return &result, nil
+}
+
+// sha1Seeker hashes rs from its current offset and seeks back to it.
+func sha1Seeker(rs io.ReadSeeker) (string, error) {
+	offset, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	h := sha1.New()
+	if _, err := io.Copy(h, rs); err != nil {
+		return "", err
+	}
+	if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// findSha1 looks for a file with the given sha1 in folderID only.
+func (c *Client) findSha1(ctx context.Context, folderID string, sum string) (*UploadResponse, error) {
+	list, err := c.ListFolderContext(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range list.Files {
+		if strings.EqualFold(f.Sha1, sum) {
+			return &UploadResponse{
+				ContentType: f.ContentType,
+				ID:          f.Linkextid,
+				Name:        f.Name,
+				Sha1:        f.Sha1,
+				Size:        f.Size,
+				URL:         f.Link,
+			}, nil
+		}
+	}
+	return nil, nil
+}
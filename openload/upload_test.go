@@ -0,0 +1,279 @@
+package openload
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uploadServer serves /1/file/ul and /upload
+// and records the name and content of the uploaded file.
+type uploadServer struct {
+	apiServer
+	links   int
+	sha1    string
+	name    string
+	content string
+	// folders and files are the listing returned by /1/file/listfolder.
+	folders string
+	files   string
+	// folder is the folder of the last upload link request.
+	folder string
+	// wrongSha1 makes /upload report a bogus sha1.
+	wrongSha1 bool
+}
+
+func newUploadServer(t *testing.T) *uploadServer {
+	s := &uploadServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/file/ul", func(w http.ResponseWriter, r *http.Request) {
+		s.links++
+		s.sha1 = r.URL.Query().Get("sha1")
+		s.folder = r.URL.Query().Get("folder")
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"url":"%s/upload","valid_until":"2015-01-09 00:02:50"}}`, s.URL)
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("files")
+		if err != nil {
+			fmt.Fprintf(w, `{"status":500,"msg":%q}`, err.Error())
+			return
+		}
+		defer file.Close()
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			fmt.Fprintf(w, `{"status":500,"msg":%q}`, err.Error())
+			return
+		}
+		s.name, s.content = header.Filename, string(data)
+		sum := fmt.Sprintf("%x", sha1.Sum(data))
+		if s.wrongSha1 {
+			sum = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"content_type":"text/plain","id":"UPPjeAk--30","name":%q,"sha1":%q,"size":"%d","url":"https://openload.co/f/UPPjeAk--30/%s"}}`, s.name, sum, len(data), s.name)
+	})
+	mux.HandleFunc("/1/file/listfolder", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":{"folders":[%s],"files":[%s]}}`, s.folders, s.files)
+	})
+	s.apiServer = newAPIServer(t, mux)
+	return s
+}
+
+func TestUploadReader(t *testing.T) {
+	s := newUploadServer(t)
+
+	uploaded, err := s.client().UploadReader(context.Background(), strings.NewReader("The quick brown fox"), "fox.txt", UploadOptions{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "UPPjeAk--30", uploaded.ID)
+	assert.EqualValues(t, "fox.txt", uploaded.Name)
+	assert.EqualValues(t, "fox.txt", s.name)
+	assert.EqualValues(t, "The quick brown fox", s.content)
+}
+
+func TestUploadFile(t *testing.T) {
+	s := newUploadServer(t)
+	name := filepath.Join(t.TempDir(), "fox.txt")
+	assert.Nil(t, ioutil.WriteFile(name, []byte("The quick brown fox"), 0600))
+	f, err := os.Open(name)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	uploaded, err := s.client().UploadFile(context.Background(), f, UploadOptions{FolderID: "5"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fox.txt", uploaded.Name)
+	assert.EqualValues(t, "The quick brown fox", s.content)
+}
+
+func TestUpload(t *testing.T) {
+	s := newUploadServer(t)
+	name := filepath.Join(t.TempDir(), "fox.txt")
+	assert.Nil(t, ioutil.WriteFile(name, []byte("The quick brown fox"), 0600))
+
+	uploaded, err := s.client().Upload(name, "", "", false)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fox.txt", uploaded.Name)
+	assert.EqualValues(t, "The quick brown fox", s.content)
+}
+
+func TestUploadMissingFile(t *testing.T) {
+	s := newUploadServer(t)
+
+	uploaded, err := s.client().Upload(filepath.Join(t.TempDir(), "missing.txt"), "", "", false)
+
+	assert.Nil(t, uploaded)
+	assert.True(t, os.IsNotExist(err))
+	assert.EqualValues(t, 0, s.links)
+}
+
+func TestUploadDirectory(t *testing.T) {
+	s := newUploadServer(t)
+
+	uploaded, err := s.client().Upload(t.TempDir(), "", "", false)
+
+	assert.Nil(t, uploaded)
+	assert.Error(t, err)
+	assert.EqualValues(t, 0, s.links)
+}
+
+// failingReader returns n bytes then fails with err.
+type failingReader struct {
+	n   int
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, r.err
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.n -= len(p)
+	return len(p), nil
+}
+
+func TestUploadReaderError(t *testing.T) {
+	s := newUploadServer(t)
+	readErr := errors.New("disk on fire")
+
+	uploaded, err := s.client().UploadReader(context.Background(), &failingReader{n: 64 << 10, err: readErr}, "fox.txt", UploadOptions{})
+
+	assert.Nil(t, uploaded)
+	assert.True(t, errors.Is(err, readErr))
+	assert.EqualValues(t, 1, s.links)
+	assert.EqualValues(t, "", s.content)
+}
+
+func TestUploadReaderCanceled(t *testing.T) {
+	s := newUploadServer(t)
+	// r blocks after the first chunk until w is closed.
+	r, w := io.Pipe()
+	defer w.Close()
+	go w.Write([]byte("The quick brown fox"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	uploaded, err := s.client().UploadReader(ctx, r, "fox.txt", UploadOptions{})
+
+	assert.Nil(t, uploaded)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestUploadProgress(t *testing.T) {
+	s := newUploadServer(t)
+	content := strings.Repeat("x", 100<<10)
+
+	var updates []Progress
+	opts := UploadOptions{
+		Size: int64(len(content)),
+		Progress: func(p Progress) {
+			updates = append(updates, p)
+		},
+	}
+	r := struct{ io.Reader }{strings.NewReader(content)}
+	_, err := s.client().UploadReader(context.Background(), r, "fox.txt", opts)
+
+	assert.Nil(t, err)
+	assert.True(t, len(updates) > 1)
+	last := updates[len(updates)-1]
+	assert.EqualValues(t, len(content), last.BytesSent)
+	assert.EqualValues(t, len(content), last.Total)
+	assert.EqualValues(t, 0, last.ETA)
+	for i := 1; i < len(updates); i++ {
+		assert.True(t, updates[i].BytesSent > updates[i-1].BytesSent)
+	}
+}
+
+func TestUploadComputeSha1(t *testing.T) {
+	s := newUploadServer(t)
+	sum := fmt.Sprintf("%x", sha1.Sum([]byte("The quick brown fox")))
+
+	uploaded, err := s.client().UploadReader(context.Background(), strings.NewReader("The quick brown fox"), "fox.txt", UploadOptions{ComputeSha1: true})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, sum, uploaded.Sha1)
+	assert.EqualValues(t, sum, s.sha1)
+	assert.EqualValues(t, "The quick brown fox", s.content)
+}
+
+func TestUploadComputeSha1Stream(t *testing.T) {
+	s := newUploadServer(t)
+	r := struct{ io.Reader }{strings.NewReader("The quick brown fox")}
+
+	uploaded, err := s.client().UploadReader(context.Background(), r, "fox.txt", UploadOptions{ComputeSha1: true})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", s.sha1)
+	assert.EqualValues(t, fmt.Sprintf("%x", sha1.Sum([]byte("The quick brown fox"))), uploaded.Sha1)
+}
+
+func TestUploadChecksumMismatch(t *testing.T) {
+	s := newUploadServer(t)
+	s.wrongSha1 = true
+	r := struct{ io.Reader }{strings.NewReader("The quick brown fox")}
+
+	uploaded, err := s.client().UploadReader(context.Background(), r, "fox.txt", UploadOptions{ComputeSha1: true})
+
+	assert.NotNil(t, uploaded)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+	var checksumErr *ChecksumError
+	assert.True(t, errors.As(err, &checksumErr))
+	assert.EqualValues(t, fmt.Sprintf("%x", sha1.Sum([]byte("The quick brown fox"))), checksumErr.Expected)
+}
+
+func TestUploadSkipIfInFolder(t *testing.T) {
+	s := newUploadServer(t)
+	sum := fmt.Sprintf("%x", sha1.Sum([]byte("The quick brown fox")))
+	s.files = fmt.Sprintf(`{"name":"fox.txt","sha1":%q,"folderid":"5","upload_at":"1419791256","status":"active","size":"19","content_type":"text/plain","download_count":"0","cstatus":"ok","link":"https://openload.co/f/AYgHe95d1E4/fox.txt","linkextid":"AYgHe95d1E4"}`, sum)
+
+	uploaded, err := s.client().UploadReader(context.Background(), strings.NewReader("The quick brown fox"), "fox.txt", UploadOptions{FolderID: "5", ComputeSha1: true, SkipIfInFolder: true})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "AYgHe95d1E4", uploaded.ID)
+	assert.EqualValues(t, "https://openload.co/f/AYgHe95d1E4/fox.txt", uploaded.URL)
+	assert.EqualValues(t, 0, s.links)
+	assert.EqualValues(t, "", s.content)
+}
+
+func TestUploadToPath(t *testing.T) {
+	s := newUploadServer(t)
+	s.folders = `{"id":"5","name":"movies"}`
+	name := filepath.Join(t.TempDir(), "fox.txt")
+	assert.Nil(t, ioutil.WriteFile(name, []byte("The quick brown fox"), 0600))
+
+	uploaded, err := s.client().UploadToPath(context.Background(), name, "/movies", UploadOptions{})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fox.txt", uploaded.Name)
+	assert.EqualValues(t, "5", s.folder)
+	assert.EqualValues(t, "The quick brown fox", s.content)
+}
+
+func TestUploadSkipIfInFolderNeedsSha1(t *testing.T) {
+	s := newUploadServer(t)
+	r := struct{ io.Reader }{strings.NewReader("The quick brown fox")}
+
+	uploaded, err := s.client().UploadReader(context.Background(), r, "fox.txt", UploadOptions{ComputeSha1: true, SkipIfInFolder: true})
+
+	assert.Nil(t, uploaded)
+	assert.Equal(t, errSkipNeedsSha1, err)
+	assert.EqualValues(t, 0, s.links)
+}
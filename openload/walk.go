@@ -0,0 +1,193 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+)
+
+// SkipDir is returned by a WalkFunc to skip a folder:
+// returned for a folder entry the folder is not listed,
+// returned for a file entry the remaining files of its folder are skipped.
+var SkipDir = errors.New("skip this folder")
+
+// defaultWalkConcurrency is the number of folders listed concurrently by Walk.
+const defaultWalkConcurrency = 4
+
+// WithWalkConcurrency sets how many folders Walk lists concurrently.
+func WithWalkConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.walkConcurrency = n
+		}
+	}
+}
+
+// WalkEntry is a folder or a file visited by Walk.
+type WalkEntry struct {
+	// Path is the virtual path of the entry e.g. /movies/2019/file.mp4.
+	Path string
+	// Folder is set for folder entries.
+	Folder *Folder
+	// File is set for file entries.
+	File *File
+}
+
+// IsDir reports whether the entry is a folder.
+func (e WalkEntry) IsDir() bool {
+	return e.Folder != nil
+}
+
+// WalkFunc is called by Walk for each entry, err is set when listing
+// the folder entry failed, the folder is then skipped unless
+// WalkFunc returns a non nil error which stops the walk.
+type WalkFunc func(entry WalkEntry, err error) error
+
+type walkNode struct {
+	folder *Folder
+	path   string
+}
+
+// Walk traverses the folder tree under rootFolderID breadth-first
+// calling fn for every folder and file below it, root excluded.
+// Folders of a level are listed concurrently (see WithWalkConcurrency)
+// but fn is never called concurrently. Folders already visited are
+// skipped so that cycles can not cause endless walks.
+// https://openload.co/api#file-listfolder
+func (c *Client) Walk(ctx context.Context, rootFolderID string, fn WalkFunc) error {
+	visited := map[string]bool{rootFolderID: true}
+	level := []walkNode{{folder: &Folder{ID: rootFolderID}, path: "/"}}
+	for len(level) > 0 {
+		lists, errs := c.listLevel(ctx, level)
+		var next []walkNode
+		for i, node := range level {
+			if errs[i] != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if err := fn(WalkEntry{Path: node.path, Folder: node.folder}, errs[i]); err != nil && err != SkipDir {
+					return err
+				}
+				continue
+			}
+			for j := range lists[i].Folders {
+				folder := &lists[i].Folders[j]
+				if visited[folder.ID] {
+					continue
+				}
+				visited[folder.ID] = true
+				child := walkNode{folder: folder, path: path.Join(node.path, folder.Name)}
+				err := fn(WalkEntry{Path: child.path, Folder: folder}, nil)
+				if err == SkipDir {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				next = append(next, child)
+			}
+			for j := range lists[i].Files {
+				file := &lists[i].Files[j]
+				err := fn(WalkEntry{Path: path.Join(node.path, file.Name), File: file}, nil)
+				if err == SkipDir {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// listLevel lists the folders of nodes with bounded concurrency.
+func (c *Client) listLevel(ctx context.Context, nodes []walkNode) ([]*ListFolderResponse, []error) {
+	lists := make([]*ListFolderResponse, len(nodes))
+	errs := make([]error, len(nodes))
+	n := c.walkConcurrency
+	if n <= 0 {
+		n = defaultWalkConcurrency
+	}
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lists[i], errs[i] = c.ListFolderContext(ctx, nodes[i].folder.ID)
+		}(i)
+	}
+	wg.Wait()
+	return lists, errs
+}
+
+// Walker iterates over the entries of a folder tree, see WalkAll.
+type Walker struct {
+	entries chan WalkEntry
+	entry   WalkEntry
+	err     error
+	cancel  context.CancelFunc
+}
+
+// WalkAll is an iterator style Walk, entries are read with Next and Entry
+// and the walk error with Err once Next returned false:
+//
+//	w := client.WalkAll(ctx, "")
+//	defer w.Close()
+//	for w.Next() {
+//		fmt.Println(w.Entry().Path)
+//	}
+//	if err := w.Err(); err != nil {
+//		...
+//	}
+//
+// Listing errors stop the walk.
+func (c *Client) WalkAll(ctx context.Context, rootFolderID string) *Walker {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Walker{entries: make(chan WalkEntry), cancel: cancel}
+	go func() {
+		defer close(w.entries)
+		w.err = c.Walk(ctx, rootFolderID, func(entry WalkEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			select {
+			case w.entries <- entry:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return w
+}
+
+// Next advances to the next entry, it returns false when the walk is over.
+func (w *Walker) Next() bool {
+	entry, ok := <-w.entries
+	w.entry = entry
+	return ok
+}
+
+// Entry returns the current entry.
+func (w *Walker) Entry() WalkEntry {
+	return w.entry
+}
+
+// Err returns the error which stopped the walk if any.
+// It must be called after Next returned false.
+func (w *Walker) Err() error {
+	return w.err
+}
+
+// Close stops the walk and releases its resources.
+func (w *Walker) Close() {
+	w.cancel()
+	for range w.entries {
+	}
+}
@@ -0,0 +1,147 @@
+package openload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// folderServer serves /1/file/listfolder from a map of folder id to listing.
+type folderServer struct {
+	apiServer
+	mu      sync.Mutex
+	folders map[string]string
+	listed  []string
+}
+
+func newFolderServer(t *testing.T, folders map[string]string) *folderServer {
+	s := &folderServer{folders: folders}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1/file/listfolder", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("folder")
+		s.mu.Lock()
+		s.listed = append(s.listed, id)
+		listing, ok := s.folders[id]
+		s.mu.Unlock()
+		if !ok {
+			fmt.Fprint(w, `{"status":404,"msg":"folder not found","result":null}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":200,"msg":"OK","result":%s}`, listing)
+	})
+	s.apiServer = newAPIServer(t, mux)
+	return s
+}
+
+func testTree() map[string]string {
+	return map[string]string{
+		"0": `{"folders":[{"id":"1","name":"movies"},{"id":"2","name":"music"}],"files":[{"name":"readme.txt","size":"10"}]}`,
+		"1": `{"folders":[{"id":"3","name":"2019"}],"files":[{"name":"a.mp4"},{"name":"b.mp4"}]}`,
+		"2": `{"folders":[{"id":"1","name":"movies again"},{"id":"0","name":"root again"}],"files":[{"name":"song.mp3"}]}`,
+		"3": `{"folders":[],"files":[{"name":"file.mp4"}]}`,
+	}
+}
+
+func TestWalk(t *testing.T) {
+	s := newFolderServer(t, testTree())
+
+	var paths []string
+	var dirs int
+	err := s.client(WithWalkConcurrency(2)).Walk(context.Background(), "0", func(entry WalkEntry, err error) error {
+		assert.Nil(t, err)
+		paths = append(paths, entry.Path)
+		if entry.IsDir() {
+			dirs++
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"/movies",
+		"/music",
+		"/readme.txt",
+		"/movies/2019",
+		"/movies/a.mp4",
+		"/movies/b.mp4",
+		"/music/song.mp3",
+		"/movies/2019/file.mp4",
+	}, paths)
+	assert.EqualValues(t, 3, dirs)
+	assert.Len(t, s.listed, 4)
+}
+
+func TestWalkSkipDir(t *testing.T) {
+	s := newFolderServer(t, testTree())
+
+	var paths []string
+	err := s.client(WithWalkConcurrency(2)).Walk(context.Background(), "0", func(entry WalkEntry, err error) error {
+		paths = append(paths, entry.Path)
+		if entry.Path == "/movies" || entry.Path == "/readme.txt" {
+			return SkipDir
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/movies", "/music", "/readme.txt", "/music/song.mp3"}, paths)
+	assert.NotContains(t, s.listed, "1")
+}
+
+func TestWalkListingError(t *testing.T) {
+	tree := testTree()
+	delete(tree, "3")
+	s := newFolderServer(t, tree)
+
+	var failed []string
+	err := s.client(WithWalkConcurrency(2)).Walk(context.Background(), "0", func(entry WalkEntry, err error) error {
+		if err != nil {
+			assert.True(t, errors.Is(err, ErrNotFound))
+			failed = append(failed, entry.Path)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/movies/2019"}, failed)
+
+	stop := errors.New("stop")
+	err = s.client(WithWalkConcurrency(2)).Walk(context.Background(), "0", func(entry WalkEntry, err error) error {
+		if err != nil {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+}
+
+func TestWalkAll(t *testing.T) {
+	s := newFolderServer(t, testTree())
+
+	w := s.client(WithWalkConcurrency(2)).WalkAll(context.Background(), "0")
+	defer w.Close()
+	var paths []string
+	for w.Next() {
+		paths = append(paths, w.Entry().Path)
+	}
+
+	assert.Nil(t, w.Err())
+	assert.Len(t, paths, 8)
+	assert.EqualValues(t, "/movies/2019/file.mp4", paths[7])
+}
+
+func TestWalkAllClose(t *testing.T) {
+	s := newFolderServer(t, testTree())
+
+	w := s.client(WithWalkConcurrency(2)).WalkAll(context.Background(), "0")
+	assert.True(t, w.Next())
+	assert.EqualValues(t, "/movies", w.Entry().Path)
+	w.Close()
+
+	assert.False(t, w.Next())
+}